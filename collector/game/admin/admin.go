@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/service"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	"github.com/GoFurry/gofurry-game-collector/roof/env"
+	"github.com/bytedance/sonic"
+)
+
+/*
+ * @Desc: 管理接口, 手动触发采集并查看任务状态
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+// 接口响应
+type response struct {
+	Code int    `json:"code"`           // 状态标识, 1 成功 0 失败
+	Msg  string `json:"msg,omitempty"`  // 失败原因
+	Data any    `json:"data,omitempty"` // 响应数据
+}
+
+// InitAdmin 根据配置启动管理接口
+//
+//	GET  /jobs                    各任务的执行状态和上次结果
+//	POST /jobs/{name}             对全部游戏执行任务
+//	POST /jobs/{name}/{gameId}    对单个游戏执行任务, 支持 collect / players / news
+//	GET  /failed                  采集失败的游戏, 只保存在内存中, 重启后清空
+func InitAdmin() {
+	conf := env.GetServerConfig().Admin
+	if !conf.Enable {
+		return
+	}
+	if conf.Token == "" {
+		log.Error("管理接口未配置 token, 不开启管理接口")
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /jobs", listJobs)
+	mux.HandleFunc("POST /jobs/{name}", startJob)
+	mux.HandleFunc("POST /jobs/{name}/{gameId}", startJob)
+	mux.HandleFunc("GET /failed", listFailedGames)
+
+	server := &http.Server{
+		Addr:              conf.Addr,
+		Handler:           auth(conf.Token, mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		log.Info("管理接口已开启, 监听地址: ", conf.Addr)
+		if err := server.ListenAndServe(); err != nil {
+			log.Error("管理接口启动失败: ", err)
+		}
+	}()
+}
+
+// 校验请求头 Authorization: Bearer <token>
+func auth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(reqToken), []byte(token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, response{Code: common.RETURN_FAILED, Msg: "token 错误"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// 各任务的执行状态
+func listJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, response{Code: common.RETURN_SUCCESS, Data: service.GetGameService().GetJobStatusList()})
+}
+
+// 异步执行任务, 立即返回
+func startJob(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	var gameID int64
+	if idStr := r.PathValue("gameId"); idStr != "" {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil || id <= 0 {
+			writeJSON(w, http.StatusBadRequest, response{Code: common.RETURN_FAILED, Msg: "游戏id格式错误: " + idStr})
+			return
+		}
+		gameID = id
+	}
+
+	if gfErr := service.GetGameService().StartJob(name, gameID); gfErr != nil {
+		writeJSON(w, http.StatusBadRequest, response{Code: common.RETURN_FAILED, Msg: gfErr.GetMsg()})
+		return
+	}
+	log.Info("管理接口触发任务: ", name, " game_id=", gameID)
+	writeJSON(w, http.StatusAccepted, response{Code: common.RETURN_SUCCESS})
+}
+
+// 采集失败的游戏
+func listFailedGames(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, response{Code: common.RETURN_SUCCESS, Data: service.GetGameService().GetFailedGameList()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, res response) {
+	jsonResult, _ := sonic.Marshal(res)
+	w.Header().Set("Content-Type", common.APPLICATION)
+	w.WriteHeader(status)
+	w.Write(jsonResult)
+}
@@ -1,9 +1,15 @@
 package controller
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/GoFurry/gofurry-game-collector/collector/game/admin"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/mirror"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/notify"
 	"github.com/GoFurry/gofurry-game-collector/collector/game/service"
 	"github.com/GoFurry/gofurry-game-collector/common/log"
 	cs "github.com/GoFurry/gofurry-game-collector/common/service"
@@ -27,9 +33,31 @@ func (api *gameApi) InitGameCollection() {
 	}()
 	fmt.Println("Game 模块初始化开始...")
 
+	// 补齐表结构
+	if err := dao.InitTables(); err != nil {
+		log.Error("InitTables error: ", err.GetMsg())
+		return
+	}
+
+	// 游戏简介索引
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	if err := dao.NewGameIntroDao().EnsureIndexes(ctx); err != nil {
+		log.Error("EnsureIndexes error: ", err.GetMsg())
+	}
+	cancel()
+
 	// 初始化限流器
 	service.InitLimiter()
 
+	// 初始化事件通知
+	notify.InitNotifier()
+
+	// 初始化媒体镜像
+	mirror.InitMirror()
+
+	// 启动管理接口
+	admin.InitAdmin()
+
 	//初始化后执行一次 Ping
 	go service.GetGameService().Collect()
 	go service.GetGameService().CollectCurrentPlayers()
@@ -37,6 +65,84 @@ func (api *gameApi) InitGameCollection() {
 	// 定时任务执行 Ping
 	cs.AddCronJob(time.Duration(env.GetServerConfig().Collector.Game.GameInterval)*time.Hour, service.GetGameService().Collect)
 	cs.AddCronJob(time.Duration(env.GetServerConfig().Collector.Game.GamePlayerInterval)*time.Hour, service.GetGameService().CollectCurrentPlayers)
+	// 价格单独批量刷新
+	if priceInterval := env.GetServerConfig().Collector.Game.PriceInterval; priceInterval > 0 {
+		cs.AddCronJob(time.Duration(priceInterval)*time.Hour, service.GetGameService().RefreshPrices)
+	}
+	// 按标签和关键词发现候选游戏
+	if discoveryInterval := env.GetServerConfig().Collector.Game.Discovery.Interval; discoveryInterval > 0 {
+		cs.AddCronJob(time.Duration(discoveryInterval)*time.Hour, service.GetGameService().DiscoverGames)
+	}
 
 	fmt.Println("Game 模块初始化结束...")
 }
+
+// 全量补采游戏历史公告, 用于新添加的游戏
+func (api *gameApi) BackfillGameNews(idStr string) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error("receive BackfillGameNews recover: ", err)
+		}
+	}()
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		log.Error("游戏id格式错误: ", idStr)
+		return
+	}
+
+	// 补齐表结构
+	if gfErr := dao.InitTables(); gfErr != nil {
+		log.Error("InitTables error: ", gfErr.GetMsg())
+		return
+	}
+
+	// 初始化限流器
+	service.InitLimiter()
+
+	if gfErr := service.GetGameService().BackfillNews(id); gfErr != nil {
+		log.Error("BackfillNews error: ", gfErr.GetMsg())
+		return
+	}
+	log.Info("游戏 ", id, " 公告补采完成")
+}
+
+// 审核候选游戏, approve 为 true 时通过并创建游戏记录, 否则拒绝
+func (api *gameApi) ReviewCandidate(appidStr string, approve bool) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error("receive ReviewCandidate recover: ", err)
+		}
+	}()
+
+	appid, err := strconv.ParseInt(appidStr, 10, 64)
+	if err != nil {
+		log.Error("appid 格式错误: ", appidStr)
+		return
+	}
+
+	// 补齐表结构
+	if gfErr := dao.InitTables(); gfErr != nil {
+		log.Error("InitTables error: ", gfErr.GetMsg())
+		return
+	}
+
+	if !approve {
+		if gfErr := service.GetGameService().RejectCandidate(appid); gfErr != nil {
+			log.Error("RejectCandidate error: ", gfErr.GetMsg())
+			return
+		}
+		log.Info("候选游戏 ", appid, " 已拒绝")
+		return
+	}
+
+	// 初始化限流器
+	service.InitLimiter()
+
+	id, gfErr := service.GetGameService().ApproveCandidate(appid)
+	if gfErr != nil {
+		log.Error("ApproveCandidate error: ", gfErr.GetMsg())
+		return
+	}
+	log.Info("候选游戏 ", appid, " 已通过, 游戏表id: ", id)
+}
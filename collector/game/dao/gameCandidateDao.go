@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/abstract"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var newGameCandidateDao = new(gameCandidateDao)
+
+func init() {
+	newGameCandidateDao.Init()
+	newGameCandidateDao.Mode = models.GfgGameCandidate{}
+}
+
+type gameCandidateDao struct{ abstract.Dao }
+
+func GetGameCandidateDao() *gameCandidateDao { return newGameCandidateDao }
+
+// 添加候选游戏, 已存在的 appid 跳过, 返回新增数量
+func (dao gameCandidateDao) AddCandidates(list []models.GfgGameCandidate) (int64, common.GFError) {
+	if len(list) == 0 {
+		return 0, nil
+	}
+	db := dao.Gm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "appid"}},
+		DoNothing: true,
+	}).CreateInBatches(&list, 500)
+	if err := db.Error; err != nil {
+		return 0, common.NewDaoError(err.Error())
+	}
+	return db.RowsAffected, nil
+}
+
+// 获取候选游戏
+func (dao gameCandidateDao) GetCandidateByAppid(appid int64) (models.GfgGameCandidate, common.GFError) {
+	var res models.GfgGameCandidate
+	db := dao.Gm.Table(models.TableNameGfgGameCandidate).Where("appid=?", appid)
+	db.Take(&res)
+	if err := db.Error; err != nil {
+		return res, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
+// 拒绝候选游戏
+func (dao gameCandidateDao) RejectCandidate(id int64) common.GFError {
+	db := dao.Gm.Table(models.TableNameGfgGameCandidate).Where("id=?", id).Updates(map[string]any{
+		"status":      models.CANDIDATE_REJECTED,
+		"review_time": cm.LocalTime(time.Now()),
+	})
+	if err := db.Error; err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
+
+// 通过候选游戏, 同一事务中创建游戏并记录游戏表id
+func (dao gameCandidateDao) ApproveCandidate(id int64, game *models.GfgGame) common.GFError {
+	err := dao.Gm.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(game).Error; err != nil {
+			return err
+		}
+		return tx.Table(models.TableNameGfgGameCandidate).Where("id=?", id).Updates(map[string]any{
+			"status":      models.CANDIDATE_APPROVED,
+			"game_id":     game.ID,
+			"review_time": cm.LocalTime(time.Now()),
+		}).Error
+	})
+	if err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
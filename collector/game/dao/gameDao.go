@@ -20,7 +20,7 @@ func GetGameDao() *gameDao { return newGameDao }
 // 获取游戏列表
 func (dao gameDao) GetGameList() ([]models.GameID, common.GFError) {
 	var res []models.GameID
-	db := dao.Gm.Table(models.TableNameGfgGame).Select("id, appid")
+	db := dao.Gm.Table(models.TableNameGfgGame).Select("id, appid, COALESCE(itch_url, '') AS itch_url, COALESCE(gog_id, 0) AS gog_id")
 	db.Find(&res)
 	if err := db.Error; err != nil {
 		return nil, common.NewDaoError(err.Error())
@@ -28,13 +28,60 @@ func (dao gameDao) GetGameList() ([]models.GameID, common.GFError) {
 	return res, nil
 }
 
+// 获取已添加游戏的 appid
+func (dao gameDao) GetGameAppidList() ([]int64, common.GFError) {
+	var res []int64
+	db := dao.Gm.Table(models.TableNameGfgGame).Where("appid<>0").Pluck("appid", &res)
+	if err := db.Error; err != nil {
+		return nil, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
 // 获取游戏记录
-func (dao gameDao) GetGameRecordByGameIDAndLang(gameID int64, lang string) (models.GfgGameRecord, common.GFError) {
+func (dao gameDao) GetGameRecordByGameIDAndLang(gameID int64, lang string, store string) (models.GfgGameRecord, common.GFError) {
 	var res models.GfgGameRecord
-	db := dao.Gm.Table(models.TableNameGfgGameRecord).Where("game_id=? AND lang=?", gameID, lang)
+	db := dao.Gm.Table(models.TableNameGfgGameRecord).Where("game_id=? AND lang=? AND store=?", gameID, lang, store)
+	db.Take(&res)
+	if err := db.Error; err != nil {
+		return res, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
+// 获取游戏名称
+func (dao gameDao) GetGameName(gameID int64) (string, common.GFError) {
+	var res string
+	db := dao.Gm.Table(models.TableNameGfgGame).Select("name").Where("id=?", gameID)
+	db.Take(&res)
+	if err := db.Error; err != nil {
+		return res, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
+// 获取游戏的各商店id
+func (dao gameDao) GetGameByID(gameID int64) (models.GameID, common.GFError) {
+	var res models.GameID
+	db := dao.Gm.Table(models.TableNameGfgGame).Select("id, appid, COALESCE(itch_url, '') AS itch_url, COALESCE(gog_id, 0) AS gog_id").Where("id=?", gameID)
 	db.Take(&res)
 	if err := db.Error; err != nil {
 		return res, common.NewDaoError(err.Error())
 	}
 	return res, nil
 }
+
+// 更新游戏记录的价格字段, 零值同样写入, 如折扣结束后折扣归零
+func (dao gameDao) UpdateGameRecordPrice(gameID int64, lang string, store string, priceList string, initial int64, final int64, discount int64) common.GFError {
+	db := dao.Gm.Table(models.TableNameGfgGameRecord).Where("game_id=? AND lang=? AND store=?", gameID, lang, store)
+	db = db.Updates(map[string]any{
+		"price_list": priceList,
+		"initial":    initial,
+		"final":      final,
+		"discount":   discount,
+	})
+	if err := db.Error; err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package dao
+
+import (
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common/abstract"
+)
+
+var newGameEventDao = new(gameEventDao)
+
+func init() {
+	newGameEventDao.Init()
+	newGameEventDao.Mode = models.GfgGameEvent{}
+}
+
+type gameEventDao struct{ abstract.Dao }
+
+func GetGameEventDao() *gameEventDao { return newGameEventDao }
@@ -39,9 +39,19 @@ func (d *GameIntroDao) SaveOrUpdate(ctx context.Context, intro *models.GameIntro
 		"lang":    intro.Lang,
 	}
 
-	// 构建更新内容
+	// 构建更新内容, 创建时间只在新增时写入
 	update := bson.M{
-		"$set": intro, // 全量更新
+		"$set": bson.M{
+			"game_id":      intro.GameID,
+			"content":      intro.Content,
+			"lang":         intro.Lang,
+			"screenshots":  intro.Screenshots,
+			"videos":       intro.Videos,
+			"image_metas":  intro.ImageMetas,
+			"header_image": intro.HeaderImage,
+			"update_time":  intro.UpdateTime,
+		},
+		"$setOnInsert": bson.M{"create_time": intro.CreateTime},
 	}
 
 	// 执行更新
@@ -58,6 +68,18 @@ func (d *GameIntroDao) SaveOrUpdate(ctx context.Context, intro *models.GameIntro
 	return nil
 }
 
+// EnsureIndexes 创建 game_id + lang 唯一索引
+func (d *GameIntroDao) EnsureIndexes(ctx context.Context) common.GFError {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "game_id", Value: 1}, {Key: "lang", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("uk_game_id_lang"),
+	}
+	if _, err := cs.Mongo.Collection(models.GameIntro{}.TableName()).Indexes().CreateOne(ctx, index); err != nil {
+		return common.NewDaoError("创建游戏简介索引失败: " + err.Error())
+	}
+	return nil
+}
+
 // GetByGameIDAndLang 根据游戏ID+语言查询简介
 func (d *GameIntroDao) GetByGameIDAndLang(ctx context.Context, gameID int64, lang string) (res models.GameIntro, err common.GFError) {
 	if gameID == 0 || lang == "" {
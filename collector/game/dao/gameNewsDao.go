@@ -4,6 +4,7 @@ import (
 	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
 	"github.com/GoFurry/gofurry-game-collector/common"
 	"github.com/GoFurry/gofurry-game-collector/common/abstract"
+	"gorm.io/gorm/clause"
 )
 
 var newGameNewsDao = new(gameNewsDao)
@@ -17,13 +18,79 @@ type gameNewsDao struct{ abstract.Dao }
 
 func GetGameNewsDao() *gameNewsDao { return newGameNewsDao }
 
-// 获取游戏记录
-func (dao gameNewsDao) GetGameNews(gameID int64, lang string, idx int64) (models.GfgGameNews, common.GFError) {
+// 根据商店公告id获取公告记录
+func (dao gameNewsDao) GetGameNewsByGid(gameID int64, lang string, store string, gid string) (models.GfgGameNews, common.GFError) {
 	var res models.GfgGameNews
-	db := dao.Gm.Table(models.TableNameGfgGameNews).Where("game_id=? AND lang=? AND index=?", gameID, lang, idx)
+	db := dao.Gm.Table(models.TableNameGfgGameNews).Where("game_id=? AND lang=? AND store=? AND gid=?", gameID, lang, store, gid)
 	db.Take(&res)
 	if err := db.Error; err != nil {
 		return res, common.NewDaoError(err.Error())
 	}
 	return res, nil
 }
+
+// 新增公告, 同一公告id已存在时不新增, 返回是否新增
+// 依赖 (game_id, lang, store, gid) 上的部分唯一索引, 多个采集任务同时保存同一公告时只有一个新增成功
+func (dao gameNewsDao) AddNews(record *models.GfgGameNews) (bool, common.GFError) {
+	db := dao.Gm.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "game_id"}, {Name: "lang"}, {Name: "store"}, {Name: "gid"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "gid <> ''"}}},
+		DoNothing:   true,
+	}).Create(record)
+	if err := db.Error; err != nil {
+		return false, common.NewDaoError(err.Error())
+	}
+	return db.RowsAffected > 0, nil
+}
+
+// 根据原始地址获取没有公告id的旧记录
+func (dao gameNewsDao) GetLegacyGameNewsByURL(gameID int64, lang string, store string, url string) (models.GfgGameNews, common.GFError) {
+	var res models.GfgGameNews
+	db := dao.Gm.Table(models.TableNameGfgGameNews).Where("game_id=? AND lang=? AND store=? AND url=? AND COALESCE(gid, '')=''", gameID, lang, store, url)
+	db.Take(&res)
+	if err := db.Error; err != nil {
+		return res, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
+// 获取游戏已保存的公告数量
+func (dao gameNewsDao) CountGameNews(gameID int64, store string) (cnt int64, gfError common.GFError) {
+	db := dao.Gm.Table(models.TableNameGfgGameNews).Where("game_id=? AND store=?", gameID, store).Count(&cnt)
+	if err := db.Error; err != nil {
+		return 0, common.NewDaoError(err.Error())
+	}
+	return
+}
+
+// 获取最新的 cnt 篇公告, 按发布时间倒序
+// 没有公告id的旧记录按序号保存, 与按公告id保存的记录重复, 不再展示
+func (dao gameNewsDao) GetLatestGameNews(gameID int64, lang string, store string, cnt int) ([]models.GfgGameNews, common.GFError) {
+	var res []models.GfgGameNews
+	db := dao.Gm.Table(models.TableNameGfgGameNews).Where("game_id=? AND lang=? AND store=? AND COALESCE(gid, '')<>''", gameID, lang, store)
+	db = db.Order("post_time DESC, id DESC").Limit(cnt).Find(&res)
+	if err := db.Error; err != nil {
+		return nil, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
+// 获取游戏公告采集游标
+func (dao gameNewsDao) GetNewsCursor(gameID int64, store string) (models.GfgGameNewsCursor, common.GFError) {
+	var res models.GfgGameNewsCursor
+	db := dao.Gm.Table(models.TableNameGfgGameNewsCursor).Where("game_id=? AND store=?", gameID, store)
+	db.Take(&res)
+	if err := db.Error; err != nil {
+		return res, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
+// 保存游戏公告采集游标
+func (dao gameNewsDao) SaveNewsCursor(record *models.GfgGameNewsCursor) common.GFError {
+	db := dao.Gm.Save(record)
+	if err := db.Error; err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
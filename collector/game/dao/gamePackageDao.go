@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/abstract"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var newGamePackageDao = new(gamePackageDao)
+
+func init() {
+	newGamePackageDao.Init()
+	newGamePackageDao.Mode = models.GfgGamePackage{}
+}
+
+type gamePackageDao struct{ abstract.Dao }
+
+func GetGamePackageDao() *gamePackageDao { return newGamePackageDao }
+
+// 替换游戏的礼包, 不在 list 中的旧记录删除
+func (dao gamePackageDao) ReplaceGamePackages(gameID int64, list []models.GfgGamePackage) common.GFError {
+	err := dao.Gm.Transaction(func(tx *gorm.DB) error {
+		packageIDs := make([]int64, 0, len(list))
+		for _, v := range list {
+			packageIDs = append(packageIDs, v.PackageID)
+		}
+
+		db := tx.Where("game_id=?", gameID)
+		if len(packageIDs) > 0 {
+			db = db.Where("package_id NOT IN ?", packageIDs)
+		}
+		if err := db.Delete(&models.GfgGamePackage{}).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "game_id"}, {Name: "package_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "apps", "app_count", "sort", "update_time"}),
+		}).CreateInBatches(&list, 500).Error
+	})
+	if err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
+
+// 保存礼包各国区价格, 零值字段同样写入
+func (dao gamePackageDao) SavePackagePrices(list []models.GfgGamePackagePrice) common.GFError {
+	if len(list) == 0 {
+		return nil
+	}
+	db := dao.Gm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "package_id"}, {Name: "region"}},
+		DoUpdates: clause.AssignmentColumns([]string{"currency", "initial", "final", "discount", "individual", "update_time"}),
+	}).CreateInBatches(&list, 500)
+	if err := db.Error; err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
@@ -1,9 +1,13 @@
 package dao
 
 import (
+	"time"
+
 	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
 	"github.com/GoFurry/gofurry-game-collector/common"
 	"github.com/GoFurry/gofurry-game-collector/common/abstract"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"gorm.io/gorm/clause"
 )
 
 var newGamePlayerDao = new(gamePlayerDao)
@@ -17,22 +21,110 @@ type gamePlayerDao struct{ abstract.Dao }
 
 func GetGamePlayerDao() *gamePlayerDao { return newGamePlayerDao }
 
-// 获取在线人数记录数量
-func (dao gamePlayerDao) GetPlayerCountByID(id int64) (cnt int64, gfError common.GFError) {
-	db := dao.Gm.Table(models.TableNameGfgGamePlayerCount).Where("game_id=?", id).Count(&cnt)
+// 删除指定时间之前的在线人数原始记录
+func (dao gamePlayerDao) DeletePlayerCountBefore(before time.Time) (int64, common.GFError) {
+	db := dao.Gm.Where("create_time < ?", before).Delete(&models.GfgGamePlayerCount{})
 	if err := db.Error; err != nil {
 		return 0, common.NewDaoError(err.Error())
 	}
-	return
+	return db.RowsAffected, nil
+}
+
+// 获取汇总表最后一个统计周期, 没有记录时返回零值
+func (dao gamePlayerDao) GetLastBucketTime(table string) (time.Time, common.GFError) {
+	var res []models.PlayerRollup
+	db := dao.Gm.Table(table).Select("bucket_time").Order("bucket_time DESC").Limit(1).Find(&res)
+	if err := db.Error; err != nil {
+		return time.Time{}, common.NewDaoError(err.Error())
+	}
+	if len(res) == 0 {
+		return time.Time{}, nil
+	}
+	return time.Time(res[0].BucketTime), nil
+}
+
+// 将原始记录按 unit(hour/day/month) 汇总, 只统计 start 之后的记录
+func (dao gamePlayerDao) RollupPlayerCount(unit string, start time.Time) ([]models.PlayerRollup, common.GFError) {
+	var res []models.PlayerRollup
+	db := dao.Gm.Table(models.TableNameGfgGamePlayerCount).
+		Select("game_id, DATE_TRUNC(?, create_time) AS bucket_time, MIN(count) AS min, AVG(count) AS avg, MAX(count) AS max, "+
+			"(ARRAY_AGG(create_time ORDER BY count DESC, create_time ASC))[1] AS peak_time, COUNT(*) AS samples", unit).
+		Where("create_time >= ?", start).
+		Group("1, 2").
+		Find(&res)
+	if err := db.Error; err != nil {
+		return nil, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
+// 将汇总表按更大的 unit 再次汇总, 平均值按采样数加权
+func (dao gamePlayerDao) RollupPlayerStat(table string, unit string, start time.Time) ([]models.PlayerRollup, common.GFError) {
+	var res []models.PlayerRollup
+	db := dao.Gm.Table(table).
+		Select("game_id, DATE_TRUNC(?, bucket_time) AS bucket_time, MIN(min) AS min, SUM(avg * samples) / SUM(samples) AS avg, MAX(max) AS max, "+
+			"(ARRAY_AGG(peak_time ORDER BY max DESC, peak_time ASC))[1] AS peak_time, SUM(samples) AS samples", unit).
+		Where("bucket_time >= ?", start).
+		Group("1, 2").
+		Find(&res)
+	if err := db.Error; err != nil {
+		return nil, common.NewDaoError(err.Error())
+	}
+	return res, nil
 }
 
-// 获取最后一条在线人数记录的 ID
-func (dao gamePlayerDao) GetLastRecordByID(id int64, skipCount int) (recordId []int64, gfError common.GFError) {
-	db := dao.Gm.Table(models.TableNameGfgGamePlayerCount).Select("id").Where("game_id=?", id)
-	db = db.Order("create_time ASC")
-	db = db.Offset(skipCount).Find(&recordId)
+// 保存汇总结果, 已存在的统计周期覆盖更新
+func (dao gamePlayerDao) SavePlayerRollup(table string, list []models.PlayerRollup) common.GFError {
+	if len(list) == 0 {
+		return nil
+	}
+	now := time.Now()
+	rows := make([]map[string]any, 0, len(list))
+	for _, v := range list {
+		rows = append(rows, map[string]any{
+			"id":          util.GenerateId(),
+			"game_id":     v.GameID,
+			"bucket_time": v.BucketTime,
+			"min":         v.Min,
+			"avg":         v.Avg,
+			"max":         v.Max,
+			"peak_time":   v.PeakTime,
+			"samples":     v.Samples,
+			"update_time": now,
+		})
+	}
+	db := dao.Gm.Table(table).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "game_id"}, {Name: "bucket_time"}},
+		DoUpdates: clause.AssignmentColumns([]string{"min", "avg", "max", "peak_time", "samples", "update_time"}),
+	}).CreateInBatches(rows, 500)
+	if err := db.Error; err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
+
+// 获取各游戏 start 之后的峰值和出现时间, countColumn 和 timeColumn 为表中的人数和时间字段
+func (dao gamePlayerDao) GetPlayerPeakList(table string, countColumn string, timeColumn string, start time.Time) ([]models.PlayerRollup, common.GFError) {
+	var res []models.PlayerRollup
+	db := dao.Gm.Table(table).
+		Select("DISTINCT ON (game_id) game_id, "+countColumn+" AS max, "+timeColumn+" AS peak_time").
+		Where(timeColumn+" >= ?", start).
+		Order("game_id, " + countColumn + " DESC, " + timeColumn + " ASC").
+		Find(&res)
 	if err := db.Error; err != nil {
 		return nil, common.NewDaoError(err.Error())
 	}
-	return recordId, nil
+	return res, nil
+}
+
+// 保存游戏在线人数峰值
+func (dao gamePlayerDao) SavePlayerPeak(record *models.GfgGamePlayerPeak) common.GFError {
+	db := dao.Gm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "game_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"peak_24h", "peak_24h_time", "peak_30d", "peak_30d_time", "peak_all", "peak_all_time", "update_time"}),
+	}).Create(record)
+	if err := db.Error; err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
 }
@@ -0,0 +1,69 @@
+package dao
+
+import (
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/abstract"
+)
+
+var newGamePriceDao = new(gamePriceDao)
+
+func init() {
+	newGamePriceDao.Init()
+	newGamePriceDao.Mode = models.GfgGamePrice{}
+}
+
+type gamePriceDao struct{ abstract.Dao }
+
+func GetGamePriceDao() *gamePriceDao { return newGamePriceDao }
+
+// 获取游戏国区价格记录
+func (dao gamePriceDao) GetGamePrice(gameID int64, store string, region string) (models.GfgGamePrice, common.GFError) {
+	var res models.GfgGamePrice
+	db := dao.Gm.Table(models.TableNameGfgGamePrice).Where("game_id=? AND store=? AND region=?", gameID, store, region)
+	db.Take(&res)
+	if err := db.Error; err != nil {
+		return res, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
+// 保存国区价格记录, 零值字段同样写入
+func (dao gamePriceDao) SavePrice(record *models.GfgGamePrice) common.GFError {
+	db := dao.Gm.Save(record)
+	if err := db.Error; err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
+
+// 获取国区最近一条价格历史
+func (dao gamePriceDao) GetLastPriceHistory(gameID int64, store string, region string) (models.GfgGamePriceHistory, common.GFError) {
+	var res models.GfgGamePriceHistory
+	db := dao.Gm.Table(models.TableNameGfgGamePriceHistory).Where("game_id=? AND store=? AND region=?", gameID, store, region)
+	db = db.Order("create_time DESC").Take(&res)
+	if err := db.Error; err != nil {
+		return res, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
+// 获取国区史低价格
+func (dao gamePriceDao) GetPriceLow(gameID int64, store string, region string) (models.GfgGamePriceLow, common.GFError) {
+	var res models.GfgGamePriceLow
+	db := dao.Gm.Table(models.TableNameGfgGamePriceLow).Where("game_id=? AND store=? AND region=?", gameID, store, region)
+	db.Take(&res)
+	if err := db.Error; err != nil {
+		return res, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
+// 保存国区史低价格
+func (dao gamePriceDao) SavePriceLow(record *models.GfgGamePriceLow) common.GFError {
+	db := dao.Gm.Save(record)
+	if err := db.Error; err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
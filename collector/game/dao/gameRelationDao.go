@@ -0,0 +1,61 @@
+package dao
+
+import (
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/abstract"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var newGameRelationDao = new(gameRelationDao)
+
+func init() {
+	newGameRelationDao.Init()
+	newGameRelationDao.Mode = models.GfgGameRelation{}
+}
+
+type gameRelationDao struct{ abstract.Dao }
+
+func GetGameRelationDao() *gameRelationDao { return newGameRelationDao }
+
+// 获取游戏的关联应用
+func (dao gameRelationDao) GetGameRelations(gameID int64) ([]models.GfgGameRelation, common.GFError) {
+	var res []models.GfgGameRelation
+	db := dao.Gm.Table(models.TableNameGfgGameRelation).Where("game_id=?", gameID).Order("relation, appid").Find(&res)
+	if err := db.Error; err != nil {
+		return nil, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
+
+// 替换游戏的关联应用, 不在 list 中的旧记录删除
+func (dao gameRelationDao) ReplaceGameRelations(gameID int64, list []models.GfgGameRelation) common.GFError {
+	err := dao.Gm.Transaction(func(tx *gorm.DB) error {
+		appids := make([]int64, 0, len(list))
+		for _, v := range list {
+			appids = append(appids, v.Appid)
+		}
+
+		db := tx.Where("game_id=?", gameID)
+		if len(appids) > 0 {
+			db = db.Where("appid NOT IN ?", appids)
+		}
+		if err := db.Delete(&models.GfgGameRelation{}).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "game_id"}, {Name: "appid"}},
+			DoUpdates: clause.AssignmentColumns([]string{"relation", "name", "app_type", "is_free", "header_image",
+				"release_date", "coming_soon", "prices", "detail_time", "update_time"}),
+		}).CreateInBatches(&list, 500).Error
+	})
+	if err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
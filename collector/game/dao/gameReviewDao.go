@@ -0,0 +1,29 @@
+package dao
+
+import (
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/abstract"
+)
+
+var newGameReviewDao = new(gameReviewDao)
+
+func init() {
+	newGameReviewDao.Init()
+	newGameReviewDao.Mode = models.GfgGameReview{}
+}
+
+type gameReviewDao struct{ abstract.Dao }
+
+func GetGameReviewDao() *gameReviewDao { return newGameReviewDao }
+
+// 获取游戏各语言最新一次的评测汇总
+func (dao gameReviewDao) GetLatestGameReview(gameID int64) ([]models.GfgGameReview, common.GFError) {
+	var res []models.GfgGameReview
+	db := dao.Gm.Table(models.TableNameGfgGameReview).Where("game_id=?", gameID)
+	db = db.Select("DISTINCT ON (lang) *").Order("lang, create_time DESC").Find(&res)
+	if err := db.Error; err != nil {
+		return nil, common.NewDaoError(err.Error())
+	}
+	return res, nil
+}
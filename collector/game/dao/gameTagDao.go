@@ -0,0 +1,99 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/abstract"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var newGameTagDao = new(gameTagDao)
+
+func init() {
+	newGameTagDao.Init()
+	newGameTagDao.Mode = models.GfgGameTag{}
+}
+
+type gameTagDao struct{ abstract.Dao }
+
+func GetGameTagDao() *gameTagDao { return newGameTagDao }
+
+// 保存标签, 已存在时返回原标签id
+func (dao gameTagDao) SaveTag(kind string, store string, storeTagID int64) (int64, common.GFError) {
+	record := models.GfgGameTag{
+		ID:         util.GenerateId(),
+		Kind:       kind,
+		Store:      store,
+		StoreTagID: storeTagID,
+		UpdateTime: cm.LocalTime(time.Now()),
+	}
+	db := dao.Gm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "kind"}, {Name: "store"}, {Name: "store_tag_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"update_time"}),
+	}).Create(&record)
+	if err := db.Error; err != nil {
+		return 0, common.NewDaoError(err.Error())
+	}
+
+	// 冲突时 id 仍为新生成的值, 需要重新查询
+	var res models.GfgGameTag
+	db = dao.Gm.Table(models.TableNameGfgGameTag).Where("kind=? AND store=? AND store_tag_id=?", kind, store, storeTagID).Take(&res)
+	if err := db.Error; err != nil {
+		return 0, common.NewDaoError(err.Error())
+	}
+	return res.ID, nil
+}
+
+// 保存标签的本地化名称
+func (dao gameTagDao) SaveTagName(tagID int64, lang string, name string) common.GFError {
+	record := models.GfgGameTagName{
+		ID:         util.GenerateId(),
+		TagID:      tagID,
+		Lang:       lang,
+		Name:       name,
+		UpdateTime: cm.LocalTime(time.Now()),
+	}
+	db := dao.Gm.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tag_id"}, {Name: "lang"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "update_time"}),
+	}).Create(&record)
+	if err := db.Error; err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
+
+// 替换游戏某一类别的标签关联, 不在 links 中的旧关联删除
+func (dao gameTagDao) ReplaceGameTags(gameID int64, kind string, store string, links []models.GfgGameTagLink) common.GFError {
+	err := dao.Gm.Transaction(func(tx *gorm.DB) error {
+		tagIDs := make([]int64, 0, len(links))
+		for _, v := range links {
+			tagIDs = append(tagIDs, v.TagID)
+		}
+
+		db := tx.Where("game_id=? AND tag_id IN (SELECT id FROM "+models.TableNameGfgGameTag+" WHERE kind=? AND store=?)", gameID, kind, store)
+		if len(tagIDs) > 0 {
+			db = db.Where("tag_id NOT IN ?", tagIDs)
+		}
+		if err := db.Delete(&models.GfgGameTagLink{}).Error; err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "game_id"}, {Name: "tag_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"sort", "votes", "update_time"}),
+		}).Create(&links).Error
+	})
+	if err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package dao
+
+import (
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	database "github.com/GoFurry/gofurry-game-collector/roof/db"
+	"gorm.io/gorm"
+)
+
+// 已有表上新增的字段
+var migrateColumns = []struct {
+	model any
+	field string
+}{
+	{&models.GfgGame{}, "ItchURL"},
+	{&models.GfgGame{}, "GogID"},
+	{&models.GfgGameRecord{}, "Store"},
+	{&models.GfgGameNews{}, "Store"},
+	{&models.GfgGameNews{}, "Gid"},
+	{&models.GfgGameNews{}, "ContentRaw"},
+}
+
+// 采集器新增的表
+var migrateTables = []any{
+	&models.GfgGamePrice{},
+	&models.GfgGamePriceHistory{},
+	&models.GfgGamePriceLow{},
+	&models.GfgGameEvent{},
+	&models.GfgGamePlayerHourly{},
+	&models.GfgGamePlayerDaily{},
+	&models.GfgGamePlayerMonthly{},
+	&models.GfgGamePlayerPeak{},
+	&models.GfgGameNewsCursor{},
+	&models.GfgGameReview{},
+	&models.GfgGameTag{},
+	&models.GfgGameTagName{},
+	&models.GfgGameTagLink{},
+	&models.GfgGameRelation{},
+	&models.GfgGamePackage{},
+	&models.GfgGamePackagePrice{},
+	&models.GfgGameCandidate{},
+	&models.GfgMigration{},
+}
+
+// 一次性数据迁移, 执行后按名称记录在 gfg_migration 中, 之后不再执行
+// 已发布的迁移不能修改名称, 需要调整时新增一条
+var migrateOnce = []struct {
+	name string
+	sql  []string
+}{
+	{
+		// GOG 更新日志改为按版本拆分, 删除整篇作为一篇公告的旧记录, 之后按版本重新采集
+		name: "20261017_gog_changelog_split",
+		sql: []string{
+			`DELETE FROM ` + models.TableNameGfgGameNews + ` WHERE store = 'gog' AND headline LIKE '% Changelog' AND COALESCE(NULLIF(content_raw, ''), content) ~* '<h[1-6][ >]'`,
+		},
+	},
+	{
+		// 同一公告id只保留最早的记录, 再建立唯一索引, 没有公告id的旧记录不参与
+		name: "20261017_game_news_gid_unique",
+		sql: []string{
+			`DELETE FROM ` + models.TableNameGfgGameNews + ` a USING ` + models.TableNameGfgGameNews + ` b
+			WHERE a.gid <> '' AND a.game_id = b.game_id AND a.lang = b.lang AND a.store = b.store AND a.gid = b.gid AND a.id > b.id`,
+			`CREATE UNIQUE INDEX IF NOT EXISTS idx_gfg_game_news_gid ON ` + models.TableNameGfgGameNews + ` (game_id, lang, store, gid) WHERE gid <> ''`,
+		},
+	},
+}
+
+// InitTables 启动时补齐采集器依赖的表和字段
+func InitTables() common.GFError {
+	migrator := database.Orm.DB().Migrator()
+	if err := migrator.AutoMigrate(migrateTables...); err != nil {
+		return common.NewDaoError(err.Error())
+	}
+	for _, v := range migrateColumns {
+		if migrator.HasColumn(v.model, v.field) {
+			continue
+		}
+		if err := migrator.AddColumn(v.model, v.field); err != nil {
+			return common.NewDaoError(err.Error())
+		}
+	}
+	for _, v := range migrateOnce {
+		if err := runMigration(v.name, v.sql); err != nil {
+			return common.NewDaoError(v.name + ": " + err.Error())
+		}
+	}
+	return nil
+}
+
+// 在事务中执行未执行过的迁移并记录, 多个实例同时启动时只有一个记录成功, 其余回滚
+func runMigration(name string, sqlList []string) error {
+	return database.Orm.DB().Transaction(func(tx *gorm.DB) error {
+		var cnt int64
+		if err := tx.Model(&models.GfgMigration{}).Where("name=?", name).Count(&cnt).Error; err != nil {
+			return err
+		}
+		if cnt > 0 {
+			return nil
+		}
+		for _, v := range sqlList {
+			if err := tx.Exec(v).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(&models.GfgMigration{Name: name, CreateTime: cm.LocalTime(time.Now())}).Error
+	})
+}
@@ -0,0 +1,259 @@
+package itch
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tidwall/gjson"
+)
+
+/*
+ * @Desc: itch.io 页面请求和解析, 限流由调用方处理
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+const requestTimeout = 10 * time.Second
+
+// 请求头
+func headers() map[string]string {
+	return map[string]string{
+		"User-Agent":      common.USER_AGENT,
+		"Accept-Language": common.ACCEPT_LANGUAGE_EN,
+	}
+}
+
+// FetchData 请求游戏的 data.json
+func FetchData(gameURL string, proxy *string) (string, error) {
+	respDataStr, err := util.GetByHttpWithParams(gameURL+"/data.json", headers(), nil, requestTimeout, proxy)
+	if err != nil {
+		return "", err
+	}
+	if !gjson.Valid(respDataStr) {
+		return "", fmt.Errorf("itch.io data.json 格式错误: %s", gameURL)
+	}
+	return respDataStr, nil
+}
+
+// FetchPage 请求游戏页面
+func FetchPage(gameURL string, proxy *string) (*goquery.Document, error) {
+	return util.GetByHttpWithParamsBackDoc(gameURL, headers(), nil, requestTimeout, proxy)
+}
+
+// ResolveEmbed 通过嵌入页将数字 id 解析为游戏地址, 未找到时返回空
+func ResolveEmbed(embedURL string, proxy *string) (string, error) {
+	doc, err := util.GetByHttpWithParamsBackDoc(embedURL, headers(), nil, requestTimeout, proxy)
+	if err != nil {
+		return "", err
+	}
+	gameURL := ""
+	doc.Find("a[href*='.itch.io/']").EachWithBreak(func(i int, sel *goquery.Selection) bool {
+		gameURL = strings.TrimRight(sel.AttrOr("href", ""), "/")
+		return gameURL == ""
+	})
+	return gameURL, nil
+}
+
+// ParseDetail 将 data.json 和游戏页面转换为商店详情
+func ParseDetail(gameURL string, dataStr string, doc *goquery.Document) models.StoreDetail {
+	var detail models.StoreDetail
+	detail.Name = gjson.Get(dataStr, "title").String()              // 游戏名称
+	detail.IsFree = gjson.Get(dataStr, "price").String() == ""      // 没有价格即免费
+	detail.HeaderImage = gjson.Get(dataStr, "cover_image").String() // 封面图
+	detail.Website = gameURL                                        // 游戏地址
+	detail.ShortDescription = doc.Find(`meta[name="description"]`).AttrOr("content", "")
+	detail.DetailedDescription, _ = doc.Find(".formatted_description").First().Html()
+	detail.AboutTheGame = detail.DetailedDescription
+	// 开发者
+	for _, v := range gjson.Get(dataStr, "authors.#.name").Array() {
+		detail.Developers = append(detail.Developers, v.String())
+	}
+	detail.Publishers = detail.Developers
+
+	// 游戏图片
+	detail.Screenshots = []models.SteamAppScreenshot{}
+	doc.Find(".screenshot_list a").Each(func(i int, sel *goquery.Selection) {
+		detail.Screenshots = append(detail.Screenshots, models.SteamAppScreenshot{
+			ID:            int64(i),
+			PathThumbnail: sel.Find("img").AttrOr("src", ""),
+			PathFull:      sel.AttrOr("href", ""),
+		})
+	})
+	detail.Movies = []models.SteamAppMovie{}
+
+	// 信息面板
+	doc.Find(".game_info_panel_widget table tr").Each(func(i int, sel *goquery.Selection) {
+		cells := sel.Find("td")
+		label := strings.TrimSpace(cells.First().Text())
+		value := cells.Last()
+		switch label {
+		case "Published", "Release date":
+			// 优先取 abbr 中的完整日期
+			date := value.Find("abbr").AttrOr("title", strings.TrimSpace(value.Text()))
+			date, _, _ = strings.Cut(date, "@")
+			detail.ReleaseDate.Date = strings.TrimSpace(date)
+		case "Status":
+			detail.ReleaseDate.ComingSoon = strings.Contains(strings.ToLower(value.Text()), "development")
+		case "Platforms":
+			platforms := strings.ToLower(value.Text())
+			detail.Platforms.Windows = strings.Contains(platforms, "windows")
+			detail.Platforms.Mac = strings.Contains(platforms, "macos")
+			detail.Platforms.Linux = strings.Contains(platforms, "linux")
+		case "Languages":
+			detail.SupportedLanguages = strings.TrimSpace(value.Text())
+		}
+	})
+
+	return detail
+}
+
+// ParsePrice 从 data.json 和游戏页面解析价格, 免费游戏返回 false
+// 货币代码取页面商品信息中的 priceCurrency, 页面没有时根据无歧义的货币符号判断
+func ParsePrice(dataStr string, doc *goquery.Document) (models.SteamAppPrice, bool) {
+	priceStr := gjson.Get(dataStr, "price").String()
+	if priceStr == "" {
+		return models.SteamAppPrice{}, false
+	}
+
+	// 打折时 original_price 为原价
+	initialStr := gjson.Get(dataStr, "original_price").String()
+	if initialStr == "" {
+		initialStr = priceStr
+	}
+
+	currency := ""
+	if doc != nil {
+		currency = strings.ToUpper(strings.TrimSpace(doc.Find(`[itemprop="priceCurrency"]`).First().AttrOr("content", "")))
+	}
+	if currency == "" {
+		currency = currencyBySymbol(priceStr)
+	}
+
+	return models.SteamAppPrice{
+		Initial:          ParseAmount(initialStr, currency),
+		Final:            ParseAmount(priceStr, currency),
+		Currency:         currency,
+		DiscountPercent:  gjson.Get(dataStr, "sale.rate").Int(),
+		InitialFormatted: initialStr,
+		FinalFormatted:   priceStr,
+	}, true
+}
+
+// 可以唯一确定货币的符号, ¥ 同时用于日元和人民币, 不根据符号判断
+var currencySymbol = map[string]string{
+	"$": "USD",
+	"€": "EUR",
+	"£": "GBP",
+}
+
+func currencyBySymbol(priceStr string) string {
+	for symbol, code := range currencySymbol {
+		if strings.Contains(priceStr, symbol) {
+			return code
+		}
+	}
+	return ""
+}
+
+// 没有小数单位的货币, ISO 4217 中小数位数为 0, 价格中的分隔符都是千位分隔符
+var zeroDecimalCurrency = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true, "JPY": true,
+	"KMF": true, "KRW": true, "PYG": true, "RWF": true, "UGX": true, "VND": true,
+	"VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+// ParseAmount 将 $5.00、5,00 €、¥1,000 等形式的价格转换为 100 倍的整数, 如 $5.00 记为 500
+// 与 Steam 一致, 无小数单位的货币(如日元)同样乘以 100, ¥1,200 记为 120000
+func ParseAmount(priceStr string, currency string) int64 {
+	var num strings.Builder
+	for _, r := range priceStr {
+		if (r >= '0' && r <= '9') || r == '.' || r == ',' {
+			num.WriteRune(r)
+		}
+	}
+	s := strings.Trim(num.String(), ".,")
+
+	// 最后一个分隔符后不超过两位时为小数点, 否则为千位分隔符
+	intPart, fracPart := s, ""
+	if !zeroDecimalCurrency[currency] {
+		if i := strings.LastIndexAny(s, ".,"); i >= 0 && len(s)-i-1 <= 2 {
+			intPart, fracPart = s[:i], s[i+1:]
+		}
+	}
+	intPart = strings.NewReplacer(".", "", ",", "").Replace(intPart)
+	fracPart += strings.Repeat("0", 2-len(fracPart))
+
+	price, _ := strconv.ParseInt(intPart+fracPart, 10, 64)
+	return price
+}
+
+// 开发日志 RSS
+type devlogRss struct {
+	Items []struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		PubDate     string `xml:"pubDate"`
+	} `xml:"channel>item"`
+}
+
+// FetchDevlog 请求开发日志 RSS, 返回最新 cnt 篇
+func FetchDevlog(gameURL string, cnt int, proxy *string) ([]models.SteamAppNews, error) {
+	respDataStr, err := util.GetByHttpWithParams(gameURL+"/devlog.rss", headers(), nil, requestTimeout, proxy)
+	if err != nil {
+		return nil, err
+	}
+	var rss devlogRss
+	if err = xml.Unmarshal([]byte(respDataStr), &rss); err != nil {
+		return nil, fmt.Errorf("itch.io devlog.rss 解析失败: %w", err)
+	}
+
+	// 作者取游戏页面的用户名
+	author := ""
+	if u, err := url.Parse(gameURL); err == nil {
+		author, _, _ = strings.Cut(u.Host, ".")
+	}
+
+	loc, _ := time.LoadLocation("Asia/Shanghai") // 中国 CST（UTC+8）
+	var newsList []models.SteamAppNews
+	for i, item := range rss.Items {
+		if i >= cnt {
+			break
+		}
+		postTime, _ := time.Parse(time.RFC1123Z, item.PubDate)
+		if postTime.IsZero() {
+			postTime, _ = time.Parse(time.RFC1123, item.PubDate)
+		}
+		newsList = append(newsList, models.SteamAppNews{
+			Gid:      DevlogGid(item.Link),
+			Title:    item.Title,
+			Author:   author,
+			URL:      item.Link,
+			Contents: item.Description,
+			Date:     cm.LocalTime(postTime.In(loc)),
+			Count:    int64(len(rss.Items)),
+		})
+	}
+	return newsList, nil
+}
+
+// 开发日志地址中的数字 id, 如 https://<作者>.itch.io/<游戏>/devlog/<id>/<slug>
+var devlogIDPattern = regexp.MustCompile(`/devlog/(\d+)`)
+
+// DevlogGid 开发日志的 gid, 取地址中的数字 id, 地址格式不符时取地址的 md5, 保证不超过 gid 字段长度
+func DevlogGid(link string) string {
+	if match := devlogIDPattern.FindStringSubmatch(link); match != nil {
+		return match[1]
+	}
+	return util.MD5(link)
+}
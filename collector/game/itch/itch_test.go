@@ -0,0 +1,230 @@
+package itch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 本地 fixture 服务, 按路径返回 testdata 中的文件
+func newFixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	files := map[string]string{
+		"/furry-adventure":            "page.html",
+		"/furry-adventure/data.json":  "data.json",
+		"/furry-adventure/devlog.rss": "devlog.rss",
+		"/kemono-quest":               "page_jpy.html",
+		"/kemono-quest/data.json":     "data_jpy.json",
+		"/free-paws/data.json":        "data_free.json",
+		"/broken/data.json":           "devlog.rss",
+		"/embed/1234567":              "embed.html",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := files[r.URL.Path]
+		if !ok || name == "" {
+			http.NotFound(w, r)
+			return
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+var noProxy = ""
+
+func fetchFixture(t *testing.T, gameURL string) (string, *goquery.Document) {
+	t.Helper()
+	dataStr, err := FetchData(gameURL, &noProxy)
+	if err != nil {
+		t.Fatalf("FetchData(%s): %v", gameURL, err)
+	}
+	doc, err := FetchPage(gameURL, &noProxy)
+	if err != nil {
+		t.Fatalf("FetchPage(%s): %v", gameURL, err)
+	}
+	return dataStr, doc
+}
+
+func TestFetchDataInvalidJSON(t *testing.T) {
+	server := newFixtureServer(t)
+	if _, err := FetchData(server.URL+"/broken", &noProxy); err == nil {
+		t.Fatal("FetchData 应拒绝非 json 响应")
+	}
+}
+
+func TestParseDetail(t *testing.T) {
+	server := newFixtureServer(t)
+	gameURL := server.URL + "/furry-adventure"
+	dataStr, doc := fetchFixture(t, gameURL)
+
+	detail := ParseDetail(gameURL, dataStr, doc)
+	if detail.Name != "Furry Adventure" {
+		t.Errorf("Name = %q", detail.Name)
+	}
+	if detail.IsFree {
+		t.Error("付费游戏不应为免费")
+	}
+	if detail.ShortDescription != "A cozy adventure through the forest." {
+		t.Errorf("ShortDescription = %q", detail.ShortDescription)
+	}
+	if detail.DetailedDescription != "<p>Explore the <strong>forest</strong>.</p>" {
+		t.Errorf("DetailedDescription = %q", detail.DetailedDescription)
+	}
+	if len(detail.Developers) != 2 || detail.Developers[0] != "Wolf Studio" || detail.Developers[1] != "Fox Art" {
+		t.Errorf("Developers = %v", detail.Developers)
+	}
+	if len(detail.Screenshots) != 2 || detail.Screenshots[1].PathFull != "https://img.itch.zone/shot2.png" ||
+		detail.Screenshots[1].PathThumbnail != "https://img.itch.zone/shot2_347.png" {
+		t.Errorf("Screenshots = %+v", detail.Screenshots)
+	}
+	if detail.ReleaseDate.Date != "01 March 2025" || !detail.ReleaseDate.ComingSoon {
+		t.Errorf("ReleaseDate = %+v", detail.ReleaseDate)
+	}
+	if !detail.Platforms.Windows || detail.Platforms.Mac || !detail.Platforms.Linux {
+		t.Errorf("Platforms = %+v", detail.Platforms)
+	}
+	if detail.SupportedLanguages != "English, Japanese" {
+		t.Errorf("SupportedLanguages = %q", detail.SupportedLanguages)
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	server := newFixtureServer(t)
+
+	// 打折中的美元价格
+	dataStr, doc := fetchFixture(t, server.URL+"/furry-adventure")
+	price, ok := ParsePrice(dataStr, doc)
+	if !ok {
+		t.Fatal("付费游戏应有价格")
+	}
+	if price.Currency != "USD" || price.Initial != 499 || price.Final != 399 || price.DiscountPercent != 20 {
+		t.Errorf("USD price = %+v", price)
+	}
+	if price.InitialFormatted != "$4.99" || price.FinalFormatted != "$3.99" {
+		t.Errorf("USD formatted = %q %q", price.InitialFormatted, price.FinalFormatted)
+	}
+
+	// ¥ 的货币取页面中的 priceCurrency, 日元没有小数单位, 与 Steam 一样按 100 倍记录
+	dataStr, doc = fetchFixture(t, server.URL+"/kemono-quest")
+	price, ok = ParsePrice(dataStr, doc)
+	if !ok || price.Currency != "JPY" || price.Initial != 120000 || price.Final != 120000 {
+		t.Errorf("JPY price = %+v", price)
+	}
+
+	// 免费游戏
+	dataStr, err := FetchData(server.URL+"/free-paws", &noProxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok = ParsePrice(dataStr, nil); ok {
+		t.Error("免费游戏不应有价格")
+	}
+}
+
+func TestParsePriceWithoutPage(t *testing.T) {
+	// 页面没有货币信息时只根据无歧义的符号判断
+	price, ok := ParsePrice(`{"price":"€5,00"}`, nil)
+	if !ok || price.Currency != "EUR" || price.Final != 500 {
+		t.Errorf("EUR price = %+v", price)
+	}
+	price, ok = ParsePrice(`{"price":"¥12.00"}`, nil)
+	if !ok || price.Currency != "" || price.Final != 1200 {
+		t.Errorf("¥ price = %+v", price)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		price    string
+		currency string
+		want     int64
+	}{
+		{"$5.00", "USD", 500},
+		{"$5", "USD", 500},
+		{"$0.99", "USD", 99},
+		{"$1,000", "USD", 100000},
+		{"$1,000.50", "USD", 100050},
+		{"5,00 €", "EUR", 500},
+		{"1.234,56 €", "EUR", 123456},
+		{"£7.5", "GBP", 750},
+		{"¥1,200", "JPY", 120000},
+		{"¥500", "JPY", 50000},
+		{"₩12,000", "KRW", 1200000},
+		{"¥1.200", "JPY", 120000},
+		{"¥68.00", "CNY", 6800},
+		{"¥68", "CNY", 6800},
+		{"", "USD", 0},
+	}
+	for _, c := range cases {
+		if got := ParseAmount(c.price, c.currency); got != c.want {
+			t.Errorf("ParseAmount(%q, %q) = %d, want %d", c.price, c.currency, got, c.want)
+		}
+	}
+}
+
+func TestFetchDevlog(t *testing.T) {
+	server := newFixtureServer(t)
+	newsList, err := FetchDevlog(server.URL+"/furry-adventure", 5, &noProxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(newsList) != 2 {
+		t.Fatalf("len(newsList) = %d", len(newsList))
+	}
+
+	first := newsList[0]
+	if first.Gid != "876543" {
+		t.Errorf("Gid = %q", first.Gid)
+	}
+	if first.URL != "https://wolfstudio.itch.io/furry-adventure/devlog/876543/version-12-released-with-new-forest-area-and-bug-fixes" {
+		t.Errorf("URL = %q", first.URL)
+	}
+	if first.Title != "Version 1.2 released" || first.Contents != "<p>New forest area.</p>" || first.Count != 2 {
+		t.Errorf("news = %+v", first)
+	}
+	if want := time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC); !time.Time(first.Date).Equal(want) {
+		t.Errorf("Date = %v, want %v", time.Time(first.Date), want)
+	}
+	// RFC1123 格式的发布时间
+	if want := time.Date(2026, 9, 1, 20, 15, 0, 0, time.UTC); !time.Time(newsList[1].Date).Equal(want) {
+		t.Errorf("Date = %v, want %v", time.Time(newsList[1].Date), want)
+	}
+
+	// 只返回最新 cnt 篇
+	newsList, err = FetchDevlog(server.URL+"/furry-adventure", 1, &noProxy)
+	if err != nil || len(newsList) != 1 {
+		t.Errorf("cnt=1: len=%d err=%v", len(newsList), err)
+	}
+}
+
+func TestDevlogGid(t *testing.T) {
+	if gid := DevlogGid("https://a.itch.io/b/devlog/123/slug"); gid != "123" {
+		t.Errorf("DevlogGid = %q", gid)
+	}
+	// 格式不符时取 md5, 长度固定 32
+	if gid := DevlogGid("https://a.itch.io/b/posts/some-very-long-slug-that-would-not-fit-into-a-varchar-64-column"); len(gid) != 32 {
+		t.Errorf("DevlogGid = %q", gid)
+	}
+}
+
+func TestResolveEmbed(t *testing.T) {
+	server := newFixtureServer(t)
+	gameURL, err := ResolveEmbed(server.URL+"/embed/1234567", &noProxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gameURL != "https://wolfstudio.itch.io/furry-adventure" {
+		t.Errorf("gameURL = %q", gameURL)
+	}
+}
@@ -0,0 +1,153 @@
+package mirror
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // 注册 gif 解码
+	"image/jpeg"
+	_ "image/png" // 注册 png 解码
+	"strconv"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/buckket/go-blurhash"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册 webp 解码
+)
+
+/*
+ * @Desc: 镜像图片处理, 生成缩略图、webp、blurhash 和主色调
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+const (
+	jpegQuality    = 85 // 缩略图 jpeg 质量
+	sampleWidth    = 32 // 计算 blurhash 和主色调时的采样宽度
+	blurhashX      = 4  // blurhash 横向分量数
+	blurhashY      = 3  // blurhash 纵向分量数
+	colorBucketBit = 4  // 主色调统计时每个通道保留的位数
+)
+
+// FetchImage 镜像图片并生成缩略图等信息, 已处理过的源地址直接返回缓存结果
+// 无法解码的图片只镜像原图, Image 为空
+func (m *Mirror) FetchImage(src string) (Media, error) {
+	if media, ok := m.cached(src); ok && media.Processed {
+		return media, nil
+	}
+	data, contentType, err := util.DownloadByHttp(src, nil, m.maxSize, downloadTimeout, m.proxy)
+	if err != nil {
+		return Media{}, fmt.Errorf("下载 %s 失败: %w", src, err)
+	}
+	media, err := m.save(src, data, contentType)
+	if err != nil {
+		return Media{}, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		log.Warn("图片解码失败, 不生成缩略图 ", src, ": ", err)
+	} else if media.Image, err = m.processImage(media, img); err != nil {
+		// 原图已镜像, 缩略图下次采集时重新生成
+		log.Warn("生成缩略图失败 ", src, ": ", err)
+		return media, nil
+	}
+	media.Processed = true
+	m.setCache(media)
+	return media, nil
+}
+
+// 生成各宽度的 jpeg 和 webp 缩略图, 宽度不小于原图的跳过
+func (m *Mirror) processImage(media Media, img image.Image) (*models.ImageMeta, error) {
+	bounds := img.Bounds()
+	meta := &models.ImageMeta{Width: bounds.Dx(), Height: bounds.Dy()}
+	if meta.Width == 0 || meta.Height == 0 {
+		return meta, nil
+	}
+
+	sample := resize(img, sampleWidth, draw.ApproxBiLinear)
+	hash, err := blurhash.Encode(blurhashX, blurhashY, sample)
+	if err != nil {
+		log.Warn("生成 blurhash 失败 ", media.Source, ": ", err)
+	}
+	meta.Blurhash = hash
+	meta.DominantColor = dominantColor(sample)
+
+	base := "media/" + media.Hash[:2] + "/" + media.Hash + "_"
+	for _, width := range m.widths {
+		if width <= 0 || width >= meta.Width {
+			continue
+		}
+		dst := resize(img, width, draw.CatmullRom)
+		height := dst.Bounds().Dy()
+
+		var buf bytes.Buffer
+		if err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, fmt.Errorf("生成 jpeg 缩略图失败: %w", err)
+		}
+		jpegURL, err := m.put(base+strconv.Itoa(width)+".jpg", buf.Bytes(), "image/jpeg")
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Reset()
+		if err = nativewebp.Encode(&buf, dst, nil); err != nil {
+			return nil, fmt.Errorf("生成 webp 缩略图失败: %w", err)
+		}
+		webpURL, err := m.put(base+strconv.Itoa(width)+".webp", buf.Bytes(), "image/webp")
+		if err != nil {
+			return nil, err
+		}
+
+		meta.Variants = append(meta.Variants,
+			models.ImageVariant{Width: width, Height: height, Format: "jpeg", URL: jpegURL},
+			models.ImageVariant{Width: width, Height: height, Format: "webp", URL: webpURL},
+		)
+	}
+	return meta, nil
+}
+
+// 按宽度等比缩放
+func resize(img image.Image, width int, scaler draw.Scaler) *image.RGBA {
+	bounds := img.Bounds()
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	scaler.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// 主色调, 统计量化后出现最多的颜色, 返回该颜色区间内像素的平均值
+func dominantColor(img *image.RGBA) string {
+	shift := 8 - colorBucketBit
+	var counts [1 << (3 * colorBucketBit)]int
+	var sums [1 << (3 * colorBucketBit)][3]int
+	for i := 0; i+3 < len(img.Pix); i += 4 {
+		if img.Pix[i+3] < 128 {
+			continue // 跳过透明像素
+		}
+		r, g, b := int(img.Pix[i]), int(img.Pix[i+1]), int(img.Pix[i+2])
+		idx := (r>>shift)<<(2*colorBucketBit) | (g>>shift)<<colorBucketBit | b>>shift
+		counts[idx]++
+		sums[idx][0] += r
+		sums[idx][1] += g
+		sums[idx][2] += b
+	}
+
+	best := -1
+	for i, v := range counts {
+		if v > 0 && (best < 0 || v > counts[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+	n := counts[best]
+	return fmt.Sprintf("#%02x%02x%02x", sums[best][0]/n, sums[best][1]/n, sums[best][2]/n)
+}
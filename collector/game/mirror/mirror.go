@@ -0,0 +1,293 @@
+package mirror
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cs "github.com/GoFurry/gofurry-game-collector/common/service"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/GoFurry/gofurry-game-collector/roof/env"
+	"github.com/bytedance/sonic"
+)
+
+/*
+ * @Desc: 商店媒体镜像, 按内容哈希去重保存到自有存储
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+const (
+	mirrorCacheTTL  = 30 * 24 * time.Hour // 源地址到镜像地址的缓存时间
+	downloadTimeout = 60 * time.Second    // 单个文件下载超时
+	hlsContentType  = "application/vnd.apple.mpegurl"
+)
+
+// Media 镜像结果
+type Media struct {
+	Source      string `json:"source"`       // 源地址
+	Hash        string `json:"hash"`         // 内容 sha256
+	Key         string `json:"key"`          // 存储路径
+	URL         string `json:"url"`          // 镜像地址
+	ContentType string `json:"content_type"` // 文件类型
+	Size        int    `json:"size"`         // 文件大小
+
+	Processed bool              `json:"processed,omitempty"` // 是否已生成图片信息
+	Image     *models.ImageMeta `json:"image,omitempty"`     // 图片信息, 仅 FetchImage
+}
+
+// Mirror 媒体镜像
+type Mirror struct {
+	storage Storage
+	baseURL string
+	maxSize int64
+	video   bool
+	widths  []int
+	proxy   *string
+}
+
+var defaultMirror *Mirror
+
+// GetMirror 获取默认镜像, 未开启时返回 nil
+func GetMirror() *Mirror { return defaultMirror }
+
+// InitMirror 根据配置初始化默认镜像
+func InitMirror() {
+	conf := env.GetServerConfig().Collector.Mirror
+	if !conf.Enable {
+		return
+	}
+	if conf.BaseURL == "" {
+		log.Error("媒体镜像未配置 base_url, 不开启镜像")
+		return
+	}
+
+	var storage Storage
+	switch conf.Storage {
+	case "", "local":
+		if conf.LocalPath == "" {
+			log.Error("媒体镜像未配置 local_path, 不开启镜像")
+			return
+		}
+		storage = NewLocalStorage(conf.LocalPath)
+	case "s3":
+		s3, err := NewS3Storage(conf.S3)
+		if err != nil {
+			log.Error("初始化 S3 存储失败: ", err)
+			return
+		}
+		storage = s3
+	default:
+		log.Error("未知的镜像存储类型: ", conf.Storage)
+		return
+	}
+
+	maxSize := conf.MaxSize
+	if maxSize <= 0 {
+		maxSize = 100
+	}
+	widths := conf.Widths
+	if len(widths) == 0 {
+		widths = []int{320, 640, 1280}
+	}
+	defaultMirror = &Mirror{
+		storage: storage,
+		baseURL: strings.TrimRight(conf.BaseURL, "/"),
+		maxSize: maxSize << 20,
+		video:   conf.Video,
+		widths:  widths,
+		proxy:   &env.GetServerConfig().Collector.Proxy,
+	}
+	log.Info("媒体镜像已开启, 存储: ", storage.Name())
+}
+
+// Video 是否镜像视频
+func (m *Mirror) Video() bool { return m != nil && m.video }
+
+// Fetch 镜像单个文件, 已镜像过的源地址直接返回缓存结果
+func (m *Mirror) Fetch(src string) (Media, error) {
+	if media, ok := m.cached(src); ok {
+		return media, nil
+	}
+	data, contentType, err := util.DownloadByHttp(src, nil, m.maxSize, downloadTimeout, m.proxy)
+	if err != nil {
+		return Media{}, fmt.Errorf("下载 %s 失败: %w", src, err)
+	}
+	return m.save(src, data, contentType)
+}
+
+// FetchHLS 镜像 HLS 视频, 递归镜像子播放列表和分片, 并将播放列表中的地址改写为镜像地址
+func (m *Mirror) FetchHLS(src string) (Media, error) {
+	if media, ok := m.cached(src); ok {
+		return media, nil
+	}
+	base, err := url.Parse(src)
+	if err != nil {
+		return Media{}, fmt.Errorf("地址格式错误 %s: %w", src, err)
+	}
+	data, _, err := util.DownloadByHttp(src, nil, m.maxSize, downloadTimeout, m.proxy)
+	if err != nil {
+		return Media{}, fmt.Errorf("下载 %s 失败: %w", src, err)
+	}
+
+	var buf bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#"):
+			// 标签中的 URI 属性, 如 #EXT-X-MEDIA #EXT-X-MAP
+			var replaceErr error
+			line = hlsURIPattern.ReplaceAllStringFunc(line, func(attr string) string {
+				uri := hlsURIPattern.FindStringSubmatch(attr)[1]
+				mirrored, err := m.fetchHLSRef(base, uri)
+				if err != nil {
+					replaceErr = err
+					return attr
+				}
+				return `URI="` + mirrored + `"`
+			})
+			if replaceErr != nil {
+				return Media{}, replaceErr
+			}
+		default:
+			// 子播放列表或分片
+			if line, err = m.fetchHLSRef(base, line); err != nil {
+				return Media{}, err
+			}
+		}
+		buf.WriteString(line + "\n")
+	}
+	if err = scanner.Err(); err != nil {
+		return Media{}, fmt.Errorf("解析播放列表 %s 失败: %w", src, err)
+	}
+	return m.save(src, buf.Bytes(), hlsContentType)
+}
+
+// HLS 标签中的 URI 属性
+var hlsURIPattern = regexp.MustCompile(`URI="([^"]*)"`)
+
+// 镜像播放列表中引用的地址, 返回镜像地址
+func (m *Mirror) fetchHLSRef(base *url.URL, ref string) (string, error) {
+	u, err := base.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("地址格式错误 %s: %w", ref, err)
+	}
+	var media Media
+	if path.Ext(u.Path) == ".m3u8" {
+		media, err = m.FetchHLS(u.String())
+	} else {
+		media, err = m.Fetch(u.String())
+	}
+	if err != nil {
+		return "", err
+	}
+	return media.URL, nil
+}
+
+// 按内容哈希保存文件, 相同内容只保存一次
+func (m *Mirror) save(src string, data []byte, contentType string) (Media, error) {
+	if contentType == "" || strings.HasPrefix(contentType, "application/octet-stream") {
+		contentType = http.DetectContentType(data)
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	key := "media/" + hash[:2] + "/" + hash + fileExt(src, contentType)
+
+	mirrored, err := m.put(key, data, contentType)
+	if err != nil {
+		return Media{}, err
+	}
+
+	media := Media{
+		Source:      src,
+		Hash:        hash,
+		Key:         key,
+		URL:         mirrored,
+		ContentType: contentType,
+		Size:        len(data),
+	}
+	m.setCache(media)
+	return media, nil
+}
+
+// 文件不存在时写入存储, 返回镜像地址
+func (m *Mirror) put(key string, data []byte, contentType string) (string, error) {
+	exist, err := m.storage.Exists(key)
+	if err != nil {
+		return "", fmt.Errorf("检查镜像文件 %s 失败: %w", key, err)
+	}
+	if !exist {
+		if err = m.storage.Put(key, data, contentType); err != nil {
+			return "", fmt.Errorf("保存镜像文件 %s 失败: %w", key, err)
+		}
+	}
+	return m.baseURL + "/" + key, nil
+}
+
+// 缓存源地址的镜像结果
+func (m *Mirror) setCache(media Media) {
+	jsonResult, _ := sonic.Marshal(media)
+	cs.SetExpire(cacheKey(media.Source), string(jsonResult), mirrorCacheTTL)
+}
+
+// 读取源地址的镜像缓存
+func (m *Mirror) cached(src string) (Media, bool) {
+	val, gfErr := cs.GetString(cacheKey(src))
+	if gfErr != nil || val == "" {
+		return Media{}, false
+	}
+	var media Media
+	if err := sonic.UnmarshalString(val, &media); err != nil || media.URL == "" {
+		return Media{}, false
+	}
+	return media, true
+}
+
+// IsMirrored 地址是否已经是镜像地址
+func (m *Mirror) IsMirrored(src string) bool {
+	return strings.HasPrefix(src, m.baseURL+"/")
+}
+
+func cacheKey(src string) string { return "mirror:" + util.MD5(src) }
+
+// 常见媒体类型的扩展名
+var contentTypeExt = map[string]string{
+	"image/jpeg":            ".jpg",
+	"image/png":             ".png",
+	"image/gif":             ".gif",
+	"image/webp":            ".webp",
+	"image/avif":            ".avif",
+	"video/mp4":             ".mp4",
+	"video/webm":            ".webm",
+	"video/mp2t":            ".ts",
+	"video/iso.segment":     ".m4s",
+	hlsContentType:          ".m3u8",
+	"application/x-mpegurl": ".m3u8",
+}
+
+// 文件扩展名, 优先根据文件类型, 其次取源地址中的扩展名
+func fileExt(src string, contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if ext, ok := contentTypeExt[strings.ToLower(mediaType)]; ok {
+		return ext
+	}
+	if u, err := url.Parse(src); err == nil {
+		if ext := strings.ToLower(path.Ext(u.Path)); len(ext) > 1 && len(ext) <= 6 {
+			return ext
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package mirror
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+/*
+ * @Desc: 镜像文件存储
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+// Storage 镜像文件存储, key 为 / 分隔的相对路径
+type Storage interface {
+	// Name 存储名称, 用于日志
+	Name() string
+	// Exists 文件是否已存在
+	Exists(key string) (bool, error)
+	// Put 写入文件, 已存在时覆盖
+	Put(key string, data []byte, contentType string) error
+}
+
+// LocalStorage 本地文件系统存储, 由 nginx 等对外提供访问
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage 创建本地存储
+func NewLocalStorage(root string) *LocalStorage {
+	return &LocalStorage{root: root}
+}
+
+func (s *LocalStorage) Name() string { return "local" }
+
+func (s *LocalStorage) Exists(key string) (bool, error) {
+	_, err := os.Stat(s.path(key))
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// Put 先写临时文件再重命名, 避免读到写了一半的文件
+func (s *LocalStorage) Put(key string, data []byte, contentType string) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStorage) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
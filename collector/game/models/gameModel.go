@@ -27,6 +27,8 @@ type GfgGame struct {
 	Header      string       `gorm:"column:header;type:character varying(255);not null;comment:游戏封面图" json:"header"`                   // 游戏封面图
 	Links       *string      `gorm:"column:links;type:json;comment:三方网站链接" json:"links"`                                               // 三方网站链接
 	Weight      int64        `gorm:"column:weight;type:bigint;not null;comment:权重" json:"weight"`                                      // 权重
+	ItchURL     string       `gorm:"column:itch_url;type:character varying(255);comment:itch.io 游戏地址或id" json:"itchUrl"`               // itch.io 游戏地址或id
+	GogID       int64        `gorm:"column:gog_id;type:bigint;comment:GOG 商品id" json:"gogId"`                                          // GOG 商品id
 }
 
 // TableName GfgGame's table name
@@ -35,8 +37,10 @@ func (*GfgGame) TableName() string {
 }
 
 type GameID struct {
-	ID    int64 `gorm:"column:id" json:"id"`
-	Appid int64 `gorm:"column:appid" json:"appid"`
+	ID      int64  `gorm:"column:id" json:"id"`
+	Appid   int64  `gorm:"column:appid" json:"appid"`
+	ItchURL string `gorm:"column:itch_url" json:"itchUrl"`
+	GogID   int64  `gorm:"column:gog_id" json:"gogId"`
 }
 
 type SteamAppPrice struct {
@@ -65,9 +69,27 @@ type SteamAppSupport struct {
 }
 
 type SteamAppScreenshot struct {
-	ID            int64  `json:"id"`
-	PathThumbnail string `json:"path_thumbnail"`
-	PathFull      string `json:"path_full"`
+	ID            int64      `json:"id"`
+	PathThumbnail string     `json:"path_thumbnail"`
+	PathFull      string     `json:"path_full"`
+	Image         *ImageMeta `json:"image,omitempty"` // 镜像后生成的缩略图等信息
+}
+
+// ImageMeta 镜像图片信息
+type ImageMeta struct {
+	Width         int            `json:"width" bson:"width"`                           // 原图宽度
+	Height        int            `json:"height" bson:"height"`                         // 原图高度
+	Blurhash      string         `json:"blurhash" bson:"blurhash"`                     // 加载占位图
+	DominantColor string         `json:"dominant_color" bson:"dominant_color"`         // 主色调 #rrggbb
+	Variants      []ImageVariant `json:"variants,omitempty" bson:"variants,omitempty"` // 缩放和转码后的图片
+}
+
+// ImageVariant 缩放或转码后的图片
+type ImageVariant struct {
+	Width  int    `json:"width" bson:"width"`
+	Height int    `json:"height" bson:"height"`
+	Format string `json:"format" bson:"format"` // jpeg / webp
+	URL    string `json:"url" bson:"url"`
 }
 
 type SteamAppMovie struct {
@@ -77,6 +99,7 @@ type SteamAppMovie struct {
 	DashAv1   string `json:"dash_av1"`
 	DashH264  string `json:"dash_h264"`
 	HlsH264   string `json:"hls_h264"`
+	Embed     string `json:"embed,omitempty"` // 外部视频嵌入地址
 }
 
 type PriceModel struct {
@@ -89,7 +112,45 @@ type PcRequirementModel struct {
 	Recommended string `json:"recommended"`
 }
 
+// StoreDetail 数据源返回的单语言商店详情
+type StoreDetail struct {
+	Name                string               // 游戏名称
+	IsFree              bool                 // 是否免费
+	SupportedLanguages  string               // 支持的语言
+	ReleaseDate         SteamAppRelease      // 发行日期
+	Platforms           SteamAppPlatform     // 支持平台
+	Developers          []string             // 开发商
+	Publishers          []string             // 发行商
+	HeaderImage         string               // 封面图
+	ShortDescription    string               // 概述
+	RequiredAge         string               // 年龄限制
+	SupportInfo         SteamAppSupport      // 开发商联系方式
+	Website             string               // 游戏官网
+	ContentDescriptors  string               // 内容描述
+	Screenshots         []SteamAppScreenshot // 游戏图片
+	Movies              []SteamAppMovie      // 游戏视频
+	DetailedDescription string               // 详情描述
+	AboutTheGame        string               // 关于游戏
+	PcRequirements      PcRequirementModel   // 配置需求
+	Genres              []StoreTag           // 类型
+	Categories          []StoreTag           // 功能, 如单人、合作、手柄支持
+	Tags                []StoreTag           // 用户标签, 按热门程度排序
+	DLC                 []int64              // DLC 和原声音乐的 appid
+	Demos               []int64              // 试玩版 appid
+	FullGame            int64                // 本体 appid, 仅 DLC 和试玩版
+	Packages            []int64              // 可购买的礼包 packageid, 如豪华版
+}
+
+// StoreTag 商店类型、功能或用户标签
+type StoreTag struct {
+	ID    int64  // 商店中的标签id
+	Name  string // 标签名称
+	Votes int64  // 用户标签的投票数
+}
+
 type GameSaveModel struct {
+	Store               string               `json:"store"`
+	Name                string               `json:"name"`
 	Price               SteamAppPrice        `json:"price"`
 	Support             SteamAppSupport      `json:"support"`
 	Screenshots         []SteamAppScreenshot `json:"screenshots"`
@@ -109,6 +170,18 @@ type GameSaveModel struct {
 	AboutTheGame        string               `json:"about_the_game"`
 	PcRequirements      PcRequirementModel   `json:"pc_requirements"`
 
+	// 过滤前的原始内容
+	DetailedDescriptionRaw string             `json:"detailed_description_raw"`
+	AboutTheGameRaw        string             `json:"about_the_game_raw"`
+	PcRequirementsRaw      PcRequirementModel `json:"pc_requirements_raw"`
+
+	HeaderImageMeta *ImageMeta `json:"header_image_meta,omitempty"` // 封面图缩略图等信息
+
+	Genres     []string `json:"genres"`     // 类型
+	Categories []string `json:"categories"` // 功能
+	Tags       []string `json:"tags"`       // 用户标签
+	HasDemo    bool     `json:"has_demo"`   // 是否有免费试玩版
+
 	CollectDate cm.LocalTime `json:"collect_date"`
 }
 
@@ -116,20 +189,21 @@ const TableNameGfgGameRecord = "gfg_game_record"
 
 // GfgGameRecord mapped from table <gfg_game_record>
 type GfgGameRecord struct {
-	ID          int64  `gorm:"column:id;type:bigint;primaryKey;comment:游戏记录表id" json:"id"`                              // 游戏记录表id
-	GameID      int64  `gorm:"column:game_id;type:bigint;not null;comment:游戏表id" json:"gameId,string"`                  // 游戏表id
-	Language    string `gorm:"column:language;type:text;not null;comment:支持语言" json:"language"`                         // 支持语言
-	ReleaseDate string `gorm:"column:release_date;type:character varying(30);not null;comment:发行时间" json:"releaseDate"` // 发行时间
-	Platform    string `gorm:"column:platform;type:character varying(50);not null;comment:支持平台" json:"platform"`        // 支持平台
-	Developer   string `gorm:"column:developer;type:character varying(100);not null;comment:开发商" json:"developer"`      // 开发商
-	Publisher   string `gorm:"column:publisher;type:character varying(100);not null;comment:发行商" json:"publisher"`      // 发行商
-	Info        string `gorm:"column:info;type:text;not null;comment:游戏概述" json:"info"`                                 // 游戏概述
-	Cover       string `gorm:"column:cover;type:character varying(255);comment:封面图" json:"cover"`                       // 封面图
-	Lang        string `gorm:"column:lang;type:character varying(20);not null;comment:记录的语言" json:"lang"`               // 记录的语言
-	PriceList   string `gorm:"column:price_list;type:json;not null;comment:游戏价格列表" json:"priceList"`                    // 游戏价格列表
-	Initial     int64  `gorm:"column:initial;type:bigint;not null;comment:游戏价格" json:"initial"`                         // 游戏价格
-	Final       int64  `gorm:"column:final;type:bigint;not null;comment:当前价格" json:"final"`                             // 当前价格
-	Discount    int64  `gorm:"column:discount;type:bigint;not null;comment:折扣百分比" json:"discount"`                      // 折扣百分比
+	ID          int64  `gorm:"column:id;type:bigint;primaryKey;comment:游戏记录表id" json:"id"`                                 // 游戏记录表id
+	GameID      int64  `gorm:"column:game_id;type:bigint;not null;comment:游戏表id" json:"gameId,string"`                     // 游戏表id
+	Language    string `gorm:"column:language;type:text;not null;comment:支持语言" json:"language"`                            // 支持语言
+	ReleaseDate string `gorm:"column:release_date;type:character varying(30);not null;comment:发行时间" json:"releaseDate"`    // 发行时间
+	Platform    string `gorm:"column:platform;type:character varying(50);not null;comment:支持平台" json:"platform"`           // 支持平台
+	Developer   string `gorm:"column:developer;type:character varying(100);not null;comment:开发商" json:"developer"`         // 开发商
+	Publisher   string `gorm:"column:publisher;type:character varying(100);not null;comment:发行商" json:"publisher"`         // 发行商
+	Info        string `gorm:"column:info;type:text;not null;comment:游戏概述" json:"info"`                                    // 游戏概述
+	Cover       string `gorm:"column:cover;type:character varying(255);comment:封面图" json:"cover"`                          // 封面图
+	Lang        string `gorm:"column:lang;type:character varying(20);not null;comment:记录的语言" json:"lang"`                  // 记录的语言
+	PriceList   string `gorm:"column:price_list;type:json;not null;comment:游戏价格列表" json:"priceList"`                       // 游戏价格列表
+	Initial     int64  `gorm:"column:initial;type:bigint;not null;comment:游戏价格" json:"initial"`                            // 游戏价格
+	Final       int64  `gorm:"column:final;type:bigint;not null;comment:当前价格" json:"final"`                                // 当前价格
+	Discount    int64  `gorm:"column:discount;type:bigint;not null;comment:折扣百分比" json:"discount"`                         // 折扣百分比
+	Store       string `gorm:"column:store;type:character varying(20);not null;default:'steam';comment:商店标识" json:"store"` // 商店标识
 }
 
 // TableName GfgGameRecord's table name
@@ -137,7 +211,105 @@ func (*GfgGameRecord) TableName() string {
 	return TableNameGfgGameRecord
 }
 
+const TableNameGfgGamePrice = "gfg_game_price"
+
+// GfgGamePrice mapped from table <gfg_game_price>
+type GfgGamePrice struct {
+	ID               int64        `gorm:"column:id;type:bigint;primaryKey;comment:游戏价格表id" json:"id"`                                                         // 游戏价格表id
+	GameID           int64        `gorm:"column:game_id;type:bigint;not null;uniqueIndex:idx_gfg_game_price_unique;comment:游戏表id" json:"gameId,string"`       // 游戏表id
+	Store            string       `gorm:"column:store;type:character varying(20);not null;uniqueIndex:idx_gfg_game_price_unique;comment:商店标识" json:"store"`   // 商店标识
+	Region           string       `gorm:"column:region;type:character varying(10);not null;uniqueIndex:idx_gfg_game_price_unique;comment:国区代码" json:"region"` // 国区代码
+	Currency         string       `gorm:"column:currency;type:character varying(10);not null;comment:货币" json:"currency"`                                     // 货币
+	Initial          int64        `gorm:"column:initial;type:bigint;not null;comment:游戏价格" json:"initial"`                                                    // 游戏价格
+	Final            int64        `gorm:"column:final;type:bigint;not null;comment:当前价格" json:"final"`                                                        // 当前价格
+	Discount         int64        `gorm:"column:discount;type:bigint;not null;comment:折扣百分比" json:"discount"`                                                 // 折扣百分比
+	InitialFormatted string       `gorm:"column:initial_formatted;type:character varying(50);comment:游戏价格展示" json:"initialFormatted"`                         // 游戏价格展示
+	FinalFormatted   string       `gorm:"column:final_formatted;type:character varying(50);comment:当前价格展示" json:"finalFormatted"`                             // 当前价格展示
+	UpdateTime       cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                     // 更新时间
+}
+
+// TableName GfgGamePrice's table name
+func (*GfgGamePrice) TableName() string {
+	return TableNameGfgGamePrice
+}
+
+const TableNameGfgGamePriceHistory = "gfg_game_price_history"
+
+// GfgGamePriceHistory mapped from table <gfg_game_price_history>
+type GfgGamePriceHistory struct {
+	ID         int64        `gorm:"column:id;type:bigint;primaryKey;comment:价格历史表id" json:"id"`                                                         // 价格历史表id
+	GameID     int64        `gorm:"column:game_id;type:bigint;not null;index:idx_gfg_game_price_history_game;comment:游戏表id" json:"gameId,string"`       // 游戏表id
+	Store      string       `gorm:"column:store;type:character varying(20);not null;index:idx_gfg_game_price_history_game;comment:商店标识" json:"store"`   // 商店标识
+	Region     string       `gorm:"column:region;type:character varying(10);not null;index:idx_gfg_game_price_history_game;comment:国区代码" json:"region"` // 国区代码
+	Currency   string       `gorm:"column:currency;type:character varying(10);not null;comment:货币" json:"currency"`                                     // 货币
+	Initial    int64        `gorm:"column:initial;type:bigint;not null;comment:游戏价格" json:"initial"`                                                    // 游戏价格
+	Final      int64        `gorm:"column:final;type:bigint;not null;comment:当前价格" json:"final"`                                                        // 当前价格
+	Discount   int64        `gorm:"column:discount;type:bigint;not null;comment:折扣百分比" json:"discount"`                                                 // 折扣百分比
+	CreateTime cm.LocalTime `gorm:"column:create_time;type:timestamp(0) without time zone;not null;comment:价格变动时间" json:"createTime"`                   // 价格变动时间
+}
+
+// TableName GfgGamePriceHistory's table name
+func (*GfgGamePriceHistory) TableName() string {
+	return TableNameGfgGamePriceHistory
+}
+
+const TableNameGfgGamePriceLow = "gfg_game_price_low"
+
+// GfgGamePriceLow mapped from table <gfg_game_price_low>
+type GfgGamePriceLow struct {
+	ID         int64        `gorm:"column:id;type:bigint;primaryKey;comment:史低价格表id" json:"id"`                                                             // 史低价格表id
+	GameID     int64        `gorm:"column:game_id;type:bigint;not null;uniqueIndex:idx_gfg_game_price_low_unique;comment:游戏表id" json:"gameId,string"`       // 游戏表id
+	Store      string       `gorm:"column:store;type:character varying(20);not null;uniqueIndex:idx_gfg_game_price_low_unique;comment:商店标识" json:"store"`   // 商店标识
+	Region     string       `gorm:"column:region;type:character varying(10);not null;uniqueIndex:idx_gfg_game_price_low_unique;comment:国区代码" json:"region"` // 国区代码
+	Currency   string       `gorm:"column:currency;type:character varying(10);not null;comment:货币" json:"currency"`                                         // 货币
+	Initial    int64        `gorm:"column:initial;type:bigint;not null;comment:史低时的原价" json:"initial"`                                                      // 史低时的原价
+	Final      int64        `gorm:"column:final;type:bigint;not null;comment:史低价格" json:"final"`                                                            // 史低价格
+	Discount   int64        `gorm:"column:discount;type:bigint;not null;comment:史低折扣百分比" json:"discount"`                                                   // 史低折扣百分比
+	LowTime    cm.LocalTime `gorm:"column:low_time;type:timestamp(0) without time zone;not null;comment:首次达到史低的时间" json:"lowTime"`                          // 首次达到史低的时间
+	UpdateTime cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                         // 更新时间
+}
+
+// TableName GfgGamePriceLow's table name
+func (*GfgGamePriceLow) TableName() string {
+	return TableNameGfgGamePriceLow
+}
+
+// 采集器事件类型
+const (
+	EVENT_SALE_STARTED         = "SaleStarted"        // 开始打折
+	EVENT_SALE_ENDED           = "SaleEnded"          // 打折结束
+	EVENT_PRICE_DROPPED        = "PriceDropped"       // 降价
+	EVENT_NEWS_POSTED          = "NewsPosted"         // 发布新公告
+	EVENT_RELEASE_DATE_CHANGED = "ReleaseDateChanged" // 发行日期变化
+)
+
+const TableNameGfgGameEvent = "gfg_game_event"
+
+// GfgGameEvent mapped from table <gfg_game_event>
+type GfgGameEvent struct {
+	ID          int64        `gorm:"column:id;type:bigint;primaryKey;comment:事件表id" json:"id,string"`                                      // 事件表id
+	GameID      int64        `gorm:"column:game_id;type:bigint;not null;index:idx_gfg_game_event_game;comment:游戏表id" json:"gameId,string"` // 游戏表id
+	Store       string       `gorm:"column:store;type:character varying(20);not null;comment:商店标识" json:"store"`                           // 商店标识
+	Region      string       `gorm:"column:region;type:character varying(10);comment:国区代码" json:"region"`                                  // 国区代码
+	GameName    string       `gorm:"column:game_name;type:character varying(255);comment:游戏名称" json:"gameName"`                            // 游戏名称
+	EventType   string       `gorm:"column:event_type;type:character varying(30);not null;index;comment:事件类型" json:"eventType"`            // 事件类型
+	Currency    string       `gorm:"column:currency;type:character varying(10);comment:货币" json:"currency"`                                // 货币
+	OldPrice    int64        `gorm:"column:old_price;type:bigint;comment:变化前价格" json:"oldPrice"`                                           // 变化前价格
+	NewPrice    int64        `gorm:"column:new_price;type:bigint;comment:变化后价格" json:"newPrice"`                                           // 变化后价格
+	OldDiscount int64        `gorm:"column:old_discount;type:bigint;comment:变化前折扣百分比" json:"oldDiscount"`                                  // 变化前折扣百分比
+	NewDiscount int64        `gorm:"column:new_discount;type:bigint;comment:变化后折扣百分比" json:"newDiscount"`                                  // 变化后折扣百分比
+	Detail      string       `gorm:"column:detail;type:text;comment:事件详情" json:"detail"`                                                   // 事件详情
+	URL         string       `gorm:"column:url;type:character varying(255);comment:相关链接" json:"url"`                                       // 相关链接
+	CreateTime  cm.LocalTime `gorm:"column:create_time;type:timestamp(0) without time zone;not null;comment:事件时间" json:"createTime"`       // 事件时间
+}
+
+// TableName GfgGameEvent's table name
+func (*GfgGameEvent) TableName() string {
+	return TableNameGfgGameEvent
+}
+
 type SteamAppNews struct {
+	Gid      string       `json:"gid"`
 	Title    string       `json:"title"`
 	Author   string       `json:"author"`
 	URL      string       `json:"url"`
@@ -181,6 +353,9 @@ type GfgGameNews struct {
 	URL        string       `gorm:"column:url;type:character varying(255);not null;comment:更新公告原始地址" json:"url"`                      // 更新公告原始地址
 	Total      int64        `gorm:"column:total;type:bigint;not null;comment:公告总数" json:"total"`                                      // 公告总数
 	Lang       string       `gorm:"column:lang;type:character varying(30);not null;comment:记录的语言" json:"lang"`                        // 记录的语言
+	Store      string       `gorm:"column:store;type:character varying(20);not null;default:'steam';comment:商店标识" json:"store"`       // 商店标识
+	Gid        string       `gorm:"column:gid;type:character varying(64);index;comment:商店公告id" json:"gid"`                            // 商店公告id
+	ContentRaw string       `gorm:"column:content_raw;type:text;comment:过滤前的更新公告内容" json:"contentRaw"`                                // 过滤前的更新公告内容
 }
 
 // TableName GfgGameNews's table name
@@ -188,6 +363,270 @@ func (*GfgGameNews) TableName() string {
 	return TableNameGfgGameNews
 }
 
+const TableNameGfgGameNewsCursor = "gfg_game_news_cursor"
+
+// GfgGameNewsCursor mapped from table <gfg_game_news_cursor>
+type GfgGameNewsCursor struct {
+	ID           int64        `gorm:"column:id;type:bigint;primaryKey;comment:公告采集游标表id" json:"id"`                                                           // 公告采集游标表id
+	GameID       int64        `gorm:"column:game_id;type:bigint;not null;uniqueIndex:idx_gfg_game_news_cursor_unique;comment:游戏表id" json:"gameId,string"`     // 游戏表id
+	Store        string       `gorm:"column:store;type:character varying(20);not null;uniqueIndex:idx_gfg_game_news_cursor_unique;comment:商店标识" json:"store"` // 商店标识
+	LastGid      string       `gorm:"column:last_gid;type:character varying(64);not null;comment:最新公告id" json:"lastGid"`                                      // 最新公告id
+	LastPostTime cm.LocalTime `gorm:"column:last_post_time;type:timestamp(0) without time zone;comment:最新公告发布时间" json:"lastPostTime"`                         // 最新公告发布时间
+	BackfillTime cm.LocalTime `gorm:"column:backfill_time;type:timestamp(0) without time zone;comment:全量补采完成时间" json:"backfillTime"`                          // 全量补采完成时间
+	UpdateTime   cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                         // 更新时间
+}
+
+// TableName GfgGameNewsCursor's table name
+func (*GfgGameNewsCursor) TableName() string {
+	return TableNameGfgGameNewsCursor
+}
+
+const TableNameGfgGameReview = "gfg_game_review"
+
+// GfgGameReview mapped from table <gfg_game_review>
+type GfgGameReview struct {
+	ID              int64        `gorm:"column:id;type:bigint;primaryKey;comment:评测汇总表id" json:"id"`                                                                    // 评测汇总表id
+	GameID          int64        `gorm:"column:game_id;type:bigint;not null;index:idx_gfg_game_review_game;comment:游戏表id" json:"gameId,string"`                         // 游戏表id
+	Lang            string       `gorm:"column:lang;type:character varying(10);not null;index:idx_gfg_game_review_game;comment:评测语言" json:"lang"`                       // 评测语言
+	TotalReviews    int64        `gorm:"column:total_reviews;type:bigint;not null;comment:评测总数" json:"totalReviews"`                                                    // 评测总数
+	TotalPositive   int64        `gorm:"column:total_positive;type:bigint;not null;comment:好评数" json:"totalPositive"`                                                   // 好评数
+	TotalNegative   int64        `gorm:"column:total_negative;type:bigint;not null;comment:差评数" json:"totalNegative"`                                                   // 差评数
+	ReviewScore     int64        `gorm:"column:review_score;type:bigint;not null;comment:评价等级" json:"reviewScore"`                                                      // 评价等级
+	ReviewScoreDesc string       `gorm:"column:review_score_desc;type:character varying(50);comment:评价描述" json:"reviewScoreDesc"`                                       // 评价描述
+	RecentReviews   int64        `gorm:"column:recent_reviews;type:bigint;not null;comment:近30天评测数" json:"recentReviews"`                                               // 近30天评测数
+	RecentPositive  int64        `gorm:"column:recent_positive;type:bigint;not null;comment:近30天好评数" json:"recentPositive"`                                             // 近30天好评数
+	RecentNegative  int64        `gorm:"column:recent_negative;type:bigint;not null;comment:近30天差评数" json:"recentNegative"`                                             // 近30天差评数
+	RecentScore     int64        `gorm:"column:recent_score;type:bigint;not null;comment:近30天评价等级" json:"recentScore"`                                                  // 近30天评价等级
+	RecentScoreDesc string       `gorm:"column:recent_score_desc;type:character varying(50);comment:近30天评价描述" json:"recentScoreDesc"`                                   // 近30天评价描述
+	CreateTime      cm.LocalTime `gorm:"column:create_time;type:timestamp(0) without time zone;not null;index:idx_gfg_game_review_game;comment:采集时间" json:"createTime"` // 采集时间
+}
+
+// TableName GfgGameReview's table name
+func (*GfgGameReview) TableName() string {
+	return TableNameGfgGameReview
+}
+
+// SteamReviewSummary appreviews 接口的 query_summary
+type SteamReviewSummary struct {
+	TotalReviews    int64  `json:"total_reviews"`
+	TotalPositive   int64  `json:"total_positive"`
+	TotalNegative   int64  `json:"total_negative"`
+	ReviewScore     int64  `json:"review_score"`
+	ReviewScoreDesc string `json:"review_score_desc"`
+}
+
+// ReviewSummary 一种语言的评测汇总
+type ReviewSummary struct {
+	All    SteamReviewSummary // 全部评测
+	Recent SteamReviewSummary // 近30天评测
+}
+
+// StorePackage 商店礼包, 如豪华版和捆绑包
+type StorePackage struct {
+	Name   string                  // 礼包名称
+	Apps   []PackageApp            // 包含的应用
+	Prices map[string]PackagePrice // 各国区价格, key 为国区代码
+}
+
+// PackageApp 礼包包含的应用
+type PackageApp struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// PackagePrice packagedetails 返回的价格
+type PackagePrice struct {
+	Currency        string `json:"currency"`
+	Initial         int64  `json:"initial"`
+	Final           int64  `json:"final"`
+	DiscountPercent int64  `json:"discount_percent"`
+	Individual      int64  `json:"individual"` // 单独购买包含应用的总价
+}
+
+// GamePackageModel 礼包 redis 记录
+type GamePackageModel struct {
+	PackageID int64                   `json:"package_id"`
+	Name      string                  `json:"name"`
+	Apps      []PackageApp            `json:"apps"`
+	Prices    map[string]PackagePrice `json:"prices"` // key 为国区代码
+}
+
+const TableNameGfgGamePackage = "gfg_game_package"
+
+// GfgGamePackage mapped from table <gfg_game_package>
+type GfgGamePackage struct {
+	ID         int64        `gorm:"column:id;type:bigint;primaryKey;comment:游戏礼包表id" json:"id"`                                                         // 游戏礼包表id
+	GameID     int64        `gorm:"column:game_id;type:bigint;not null;uniqueIndex:idx_gfg_game_package_unique;comment:游戏表id" json:"gameId,string"`     // 游戏表id
+	PackageID  int64        `gorm:"column:package_id;type:bigint;not null;uniqueIndex:idx_gfg_game_package_unique;index;comment:礼包id" json:"packageId"` // 礼包id
+	Name       string       `gorm:"column:name;type:character varying(255);comment:礼包名称" json:"name"`                                                   // 礼包名称
+	Apps       string       `gorm:"column:apps;type:text;comment:包含的应用" json:"apps"`                                                                    // 包含的应用
+	AppCount   int64        `gorm:"column:app_count;type:bigint;not null;comment:包含的应用数" json:"appCount"`                                               // 包含的应用数
+	Sort       int64        `gorm:"column:sort;type:bigint;not null;comment:排序" json:"sort"`                                                            // 排序
+	UpdateTime cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                     // 更新时间
+}
+
+// TableName GfgGamePackage's table name
+func (*GfgGamePackage) TableName() string {
+	return TableNameGfgGamePackage
+}
+
+const TableNameGfgGamePackagePrice = "gfg_game_package_price"
+
+// GfgGamePackagePrice mapped from table <gfg_game_package_price>
+type GfgGamePackagePrice struct {
+	ID         int64        `gorm:"column:id;type:bigint;primaryKey;comment:礼包价格表id" json:"id"`                                                                 // 礼包价格表id
+	PackageID  int64        `gorm:"column:package_id;type:bigint;not null;uniqueIndex:idx_gfg_game_package_price_unique;comment:礼包id" json:"packageId"`         // 礼包id
+	Region     string       `gorm:"column:region;type:character varying(10);not null;uniqueIndex:idx_gfg_game_package_price_unique;comment:国区代码" json:"region"` // 国区代码
+	Currency   string       `gorm:"column:currency;type:character varying(10);not null;comment:货币" json:"currency"`                                             // 货币
+	Initial    int64        `gorm:"column:initial;type:bigint;not null;comment:礼包价格" json:"initial"`                                                            // 礼包价格
+	Final      int64        `gorm:"column:final;type:bigint;not null;comment:当前价格" json:"final"`                                                                // 当前价格
+	Discount   int64        `gorm:"column:discount;type:bigint;not null;comment:折扣百分比" json:"discount"`                                                         // 折扣百分比
+	Individual int64        `gorm:"column:individual;type:bigint;not null;comment:单独购买总价" json:"individual"`                                                    // 单独购买总价
+	UpdateTime cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                             // 更新时间
+}
+
+// TableName GfgGamePackagePrice's table name
+func (*GfgGamePackagePrice) TableName() string {
+	return TableNameGfgGamePackagePrice
+}
+
+// 候选游戏审核状态
+const (
+	CANDIDATE_PENDING  = "pending"  // 待审核
+	CANDIDATE_APPROVED = "approved" // 已通过
+	CANDIDATE_REJECTED = "rejected" // 已拒绝
+)
+
+// SearchResult 商店搜索结果
+type SearchResult struct {
+	Appid         int64  // appid
+	Name          string // 名称
+	ReleaseDate   string // 发行日期
+	HeaderImage   string // 搜索结果中的封面图
+	Price         string // 价格展示
+	ReviewSummary string // 评测概况
+}
+
+const TableNameGfgGameCandidate = "gfg_game_candidate"
+
+// GfgGameCandidate mapped from table <gfg_game_candidate>
+type GfgGameCandidate struct {
+	ID            int64        `gorm:"column:id;type:bigint;primaryKey;comment:候选游戏表id" json:"id"`                                     // 候选游戏表id
+	Appid         int64        `gorm:"column:appid;type:bigint;not null;uniqueIndex;comment:SteamAPI appid" json:"appid"`              // SteamAPI appid
+	Name          string       `gorm:"column:name;type:character varying(255);not null;comment:游戏名称" json:"name"`                      // 游戏名称
+	ReleaseDate   string       `gorm:"column:release_date;type:character varying(50);comment:发行日期" json:"releaseDate"`                 // 发行日期
+	HeaderImage   string       `gorm:"column:header_image;type:character varying(255);comment:封面图" json:"headerImage"`                 // 封面图
+	Price         string       `gorm:"column:price;type:character varying(50);comment:价格展示" json:"price"`                              // 价格展示
+	ReviewSummary string       `gorm:"column:review_summary;type:character varying(255);comment:评测概况" json:"reviewSummary"`            // 评测概况
+	Matched       string       `gorm:"column:matched;type:text;comment:命中的标签和关键词" json:"matched"`                                      // 命中的标签和关键词
+	Status        string       `gorm:"column:status;type:character varying(20);not null;index;comment:审核状态" json:"status"`             // 审核状态
+	GameID        int64        `gorm:"column:game_id;type:bigint;comment:审核通过后创建的游戏表id" json:"gameId,string"`                          // 审核通过后创建的游戏表id
+	ReviewTime    cm.LocalTime `gorm:"column:review_time;type:timestamp(0) without time zone;comment:审核时间" json:"reviewTime"`          // 审核时间
+	CreateTime    cm.LocalTime `gorm:"column:create_time;type:timestamp(0) without time zone;not null;comment:发现时间" json:"createTime"` // 发现时间
+}
+
+// TableName GfgGameCandidate's table name
+func (*GfgGameCandidate) TableName() string {
+	return TableNameGfgGameCandidate
+}
+
+// 关联应用类型
+const (
+	RELATION_DLC        = "dlc"        // DLC
+	RELATION_SOUNDTRACK = "soundtrack" // 原声音乐
+	RELATION_DEMO       = "demo"       // 试玩版
+	RELATION_FULLGAME   = "fullgame"   // 本体
+)
+
+// RelatedApp 关联应用的基本信息
+type RelatedApp struct {
+	Name        string          // 名称
+	Type        string          // 商店中的应用类型, 如 dlc music demo game
+	IsFree      bool            // 是否免费
+	HeaderImage string          // 封面图
+	ReleaseDate SteamAppRelease // 发行日期
+}
+
+const TableNameGfgGameRelation = "gfg_game_relation"
+
+// GfgGameRelation mapped from table <gfg_game_relation>
+type GfgGameRelation struct {
+	ID          int64        `gorm:"column:id;type:bigint;primaryKey;comment:关联应用表id" json:"id"`                                                       // 关联应用表id
+	GameID      int64        `gorm:"column:game_id;type:bigint;not null;uniqueIndex:idx_gfg_game_relation_unique;comment:游戏表id" json:"gameId,string"`  // 游戏表id
+	Appid       int64        `gorm:"column:appid;type:bigint;not null;uniqueIndex:idx_gfg_game_relation_unique;comment:关联应用appid" json:"appid,string"` // 关联应用appid
+	Relation    string       `gorm:"column:relation;type:character varying(20);not null;comment:关联类型" json:"relation"`                                 // 关联类型
+	Name        string       `gorm:"column:name;type:character varying(255);comment:名称" json:"name"`                                                   // 名称
+	AppType     string       `gorm:"column:app_type;type:character varying(20);comment:应用类型" json:"appType"`                                           // 应用类型
+	IsFree      bool         `gorm:"column:is_free;type:boolean;not null;default:false;comment:是否免费" json:"isFree"`                                    // 是否免费
+	HeaderImage string       `gorm:"column:header_image;type:character varying(255);comment:封面图" json:"headerImage"`                                   // 封面图
+	ReleaseDate string       `gorm:"column:release_date;type:character varying(50);comment:发行日期" json:"releaseDate"`                                   // 发行日期
+	ComingSoon  bool         `gorm:"column:coming_soon;type:boolean;not null;default:false;comment:是否即将推出" json:"comingSoon"`                          // 是否即将推出
+	Prices      string       `gorm:"column:prices;type:text;comment:各国区价格" json:"prices"`                                                              // 各国区价格
+	DetailTime  cm.LocalTime `gorm:"column:detail_time;type:timestamp(0) without time zone;comment:基本信息采集时间" json:"detailTime"`                        // 基本信息采集时间
+	UpdateTime  cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                   // 更新时间
+}
+
+// TableName GfgGameRelation's table name
+func (*GfgGameRelation) TableName() string {
+	return TableNameGfgGameRelation
+}
+
+// 标签类别
+const (
+	TAG_KIND_GENRE    = "genre"    // 类型
+	TAG_KIND_CATEGORY = "category" // 功能
+	TAG_KIND_TAG      = "tag"      // 用户标签
+)
+
+const TableNameGfgGameTag = "gfg_game_tag"
+
+// GfgGameTag mapped from table <gfg_game_tag>
+type GfgGameTag struct {
+	ID         int64        `gorm:"column:id;type:bigint;primaryKey;comment:标签表id" json:"id"`                                                       // 标签表id
+	Kind       string       `gorm:"column:kind;type:character varying(20);not null;uniqueIndex:idx_gfg_game_tag_unique;comment:标签类别" json:"kind"`   // 标签类别
+	Store      string       `gorm:"column:store;type:character varying(20);not null;uniqueIndex:idx_gfg_game_tag_unique;comment:商店标识" json:"store"` // 商店标识
+	StoreTagID int64        `gorm:"column:store_tag_id;type:bigint;not null;uniqueIndex:idx_gfg_game_tag_unique;comment:商店标签id" json:"storeTagId"`  // 商店标签id
+	UpdateTime cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                 // 更新时间
+}
+
+// TableName GfgGameTag's table name
+func (*GfgGameTag) TableName() string {
+	return TableNameGfgGameTag
+}
+
+const TableNameGfgGameTagName = "gfg_game_tag_name"
+
+// GfgGameTagName mapped from table <gfg_game_tag_name>
+type GfgGameTagName struct {
+	ID         int64        `gorm:"column:id;type:bigint;primaryKey;comment:标签名称表id" json:"id"`                                                      // 标签名称表id
+	TagID      int64        `gorm:"column:tag_id;type:bigint;not null;uniqueIndex:idx_gfg_game_tag_name_unique;comment:标签表id" json:"tagId,string"`   // 标签表id
+	Lang       string       `gorm:"column:lang;type:character varying(10);not null;uniqueIndex:idx_gfg_game_tag_name_unique;comment:语言" json:"lang"` // 语言
+	Name       string       `gorm:"column:name;type:character varying(100);not null;comment:标签名称" json:"name"`                                       // 标签名称
+	UpdateTime cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                  // 更新时间
+}
+
+// TableName GfgGameTagName's table name
+func (*GfgGameTagName) TableName() string {
+	return TableNameGfgGameTagName
+}
+
+const TableNameGfgGameTagLink = "gfg_game_tag_link"
+
+// GfgGameTagLink mapped from table <gfg_game_tag_link>
+type GfgGameTagLink struct {
+	ID         int64        `gorm:"column:id;type:bigint;primaryKey;comment:游戏标签关联表id" json:"id"`                                                        // 游戏标签关联表id
+	GameID     int64        `gorm:"column:game_id;type:bigint;not null;uniqueIndex:idx_gfg_game_tag_link_unique;comment:游戏表id" json:"gameId,string"`     // 游戏表id
+	TagID      int64        `gorm:"column:tag_id;type:bigint;not null;uniqueIndex:idx_gfg_game_tag_link_unique;index;comment:标签表id" json:"tagId,string"` // 标签表id
+	Sort       int64        `gorm:"column:sort;type:bigint;not null;comment:排序" json:"sort"`                                                             // 排序
+	Votes      int64        `gorm:"column:votes;type:bigint;not null;comment:用户标签投票数" json:"votes"`                                                      // 用户标签投票数
+	UpdateTime cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                      // 更新时间
+}
+
+// TableName GfgGameTagLink's table name
+func (*GfgGameTagLink) TableName() string {
+	return TableNameGfgGameTagLink
+}
+
 const TableNameGfgGamePlayerCount = "gfg_game_player_count"
 
 // GfgGamePlayerCount mapped from table <gfg_game_player_count>
@@ -203,16 +642,122 @@ func (*GfgGamePlayerCount) TableName() string {
 	return TableNameGfgGamePlayerCount
 }
 
+const TableNameGfgGamePlayerHourly = "gfg_game_player_hourly"
+
+// GfgGamePlayerHourly mapped from table <gfg_game_player_hourly>
+type GfgGamePlayerHourly struct {
+	ID         int64        `gorm:"column:id;type:bigint;primaryKey;comment:每小时在线人数表ID" json:"id"`                                                                                // 每小时在线人数表ID
+	GameID     int64        `gorm:"column:game_id;type:bigint;not null;uniqueIndex:idx_gfg_game_player_hourly_unique;comment:游戏表ID" json:"gameId,string"`                         // 游戏表ID
+	BucketTime cm.LocalTime `gorm:"column:bucket_time;type:timestamp(0) without time zone;not null;uniqueIndex:idx_gfg_game_player_hourly_unique;comment:统计小时" json:"bucketTime"` // 统计小时
+	Min        int64        `gorm:"column:min;type:bigint;not null;comment:最低在线人数" json:"min"`                                                                                    // 最低在线人数
+	Avg        float64      `gorm:"column:avg;type:double precision;not null;comment:平均在线人数" json:"avg"`                                                                          // 平均在线人数
+	Max        int64        `gorm:"column:max;type:bigint;not null;comment:最高在线人数" json:"max"`                                                                                    // 最高在线人数
+	PeakTime   cm.LocalTime `gorm:"column:peak_time;type:timestamp(0) without time zone;not null;comment:最高在线人数出现时间" json:"peakTime"`                                             // 最高在线人数出现时间
+	Samples    int64        `gorm:"column:samples;type:bigint;not null;comment:原始采样数" json:"samples"`                                                                             // 原始采样数
+	UpdateTime cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                                               // 更新时间
+}
+
+// TableName GfgGamePlayerHourly's table name
+func (*GfgGamePlayerHourly) TableName() string {
+	return TableNameGfgGamePlayerHourly
+}
+
+const TableNameGfgGamePlayerDaily = "gfg_game_player_daily"
+
+// GfgGamePlayerDaily mapped from table <gfg_game_player_daily>
+type GfgGamePlayerDaily struct {
+	ID         int64        `gorm:"column:id;type:bigint;primaryKey;comment:每日在线人数表ID" json:"id"`                                                                                // 每日在线人数表ID
+	GameID     int64        `gorm:"column:game_id;type:bigint;not null;uniqueIndex:idx_gfg_game_player_daily_unique;comment:游戏表ID" json:"gameId,string"`                         // 游戏表ID
+	BucketTime cm.LocalTime `gorm:"column:bucket_time;type:timestamp(0) without time zone;not null;uniqueIndex:idx_gfg_game_player_daily_unique;comment:统计日期" json:"bucketTime"` // 统计日期
+	Min        int64        `gorm:"column:min;type:bigint;not null;comment:最低在线人数" json:"min"`                                                                                   // 最低在线人数
+	Avg        float64      `gorm:"column:avg;type:double precision;not null;comment:平均在线人数" json:"avg"`                                                                         // 平均在线人数
+	Max        int64        `gorm:"column:max;type:bigint;not null;comment:最高在线人数" json:"max"`                                                                                   // 最高在线人数
+	PeakTime   cm.LocalTime `gorm:"column:peak_time;type:timestamp(0) without time zone;not null;comment:最高在线人数出现时间" json:"peakTime"`                                            // 最高在线人数出现时间
+	Samples    int64        `gorm:"column:samples;type:bigint;not null;comment:原始采样数" json:"samples"`                                                                            // 原始采样数
+	UpdateTime cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                                              // 更新时间
+}
+
+// TableName GfgGamePlayerDaily's table name
+func (*GfgGamePlayerDaily) TableName() string {
+	return TableNameGfgGamePlayerDaily
+}
+
+const TableNameGfgGamePlayerMonthly = "gfg_game_player_monthly"
+
+// GfgGamePlayerMonthly mapped from table <gfg_game_player_monthly>
+type GfgGamePlayerMonthly struct {
+	ID         int64        `gorm:"column:id;type:bigint;primaryKey;comment:每月在线人数表ID" json:"id"`                                                                                  // 每月在线人数表ID
+	GameID     int64        `gorm:"column:game_id;type:bigint;not null;uniqueIndex:idx_gfg_game_player_monthly_unique;comment:游戏表ID" json:"gameId,string"`                         // 游戏表ID
+	BucketTime cm.LocalTime `gorm:"column:bucket_time;type:timestamp(0) without time zone;not null;uniqueIndex:idx_gfg_game_player_monthly_unique;comment:统计月份" json:"bucketTime"` // 统计月份
+	Min        int64        `gorm:"column:min;type:bigint;not null;comment:最低在线人数" json:"min"`                                                                                     // 最低在线人数
+	Avg        float64      `gorm:"column:avg;type:double precision;not null;comment:平均在线人数" json:"avg"`                                                                           // 平均在线人数
+	Max        int64        `gorm:"column:max;type:bigint;not null;comment:最高在线人数" json:"max"`                                                                                     // 最高在线人数
+	PeakTime   cm.LocalTime `gorm:"column:peak_time;type:timestamp(0) without time zone;not null;comment:最高在线人数出现时间" json:"peakTime"`                                              // 最高在线人数出现时间
+	Samples    int64        `gorm:"column:samples;type:bigint;not null;comment:原始采样数" json:"samples"`                                                                              // 原始采样数
+	UpdateTime cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"`                                                // 更新时间
+}
+
+// TableName GfgGamePlayerMonthly's table name
+func (*GfgGamePlayerMonthly) TableName() string {
+	return TableNameGfgGamePlayerMonthly
+}
+
+const TableNameGfgGamePlayerPeak = "gfg_game_player_peak"
+
+// GfgGamePlayerPeak mapped from table <gfg_game_player_peak>
+type GfgGamePlayerPeak struct {
+	ID          int64        `gorm:"column:id;type:bigint;primaryKey;comment:在线人数峰值表ID" json:"id"`                                   // 在线人数峰值表ID
+	GameID      int64        `gorm:"column:game_id;type:bigint;not null;uniqueIndex;comment:游戏表ID" json:"gameId,string"`             // 游戏表ID
+	Peak24h     int64        `gorm:"column:peak_24h;type:bigint;not null;comment:24小时峰值" json:"peak24h"`                             // 24小时峰值
+	Peak24hTime cm.LocalTime `gorm:"column:peak_24h_time;type:timestamp(0) without time zone;comment:24小时峰值时间" json:"peak24hTime"`   // 24小时峰值时间
+	Peak30d     int64        `gorm:"column:peak_30d;type:bigint;not null;comment:30天峰值" json:"peak30d"`                              // 30天峰值
+	Peak30dTime cm.LocalTime `gorm:"column:peak_30d_time;type:timestamp(0) without time zone;comment:30天峰值时间" json:"peak30dTime"`    // 30天峰值时间
+	PeakAll     int64        `gorm:"column:peak_all;type:bigint;not null;comment:历史峰值" json:"peakAll"`                               // 历史峰值
+	PeakAllTime cm.LocalTime `gorm:"column:peak_all_time;type:timestamp(0) without time zone;comment:历史峰值时间" json:"peakAllTime"`     // 历史峰值时间
+	UpdateTime  cm.LocalTime `gorm:"column:update_time;type:timestamp(0) without time zone;not null;comment:更新时间" json:"updateTime"` // 更新时间
+}
+
+// TableName GfgGamePlayerPeak's table name
+func (*GfgGamePlayerPeak) TableName() string {
+	return TableNameGfgGamePlayerPeak
+}
+
+const TableNameGfgMigration = "gfg_migration"
+
+// GfgMigration mapped from table <gfg_migration>
+type GfgMigration struct {
+	Name       string       `gorm:"column:name;type:character varying(100);primaryKey;comment:迁移名称" json:"name"`                    // 迁移名称
+	CreateTime cm.LocalTime `gorm:"column:create_time;type:timestamp(0) without time zone;not null;comment:执行时间" json:"createTime"` // 执行时间
+}
+
+// TableName GfgMigration's table name
+func (*GfgMigration) TableName() string {
+	return TableNameGfgMigration
+}
+
+// PlayerRollup 在线人数汇总查询结果
+type PlayerRollup struct {
+	GameID     int64        `gorm:"column:game_id"`
+	BucketTime cm.LocalTime `gorm:"column:bucket_time"`
+	Min        int64        `gorm:"column:min"`
+	Avg        float64      `gorm:"column:avg"`
+	Max        int64        `gorm:"column:max"`
+	PeakTime   cm.LocalTime `gorm:"column:peak_time"`
+	Samples    int64        `gorm:"column:samples"`
+}
+
 // GameIntro 游戏简介HTML存储模型
 type GameIntro struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`                  // MongoDB自动生成的ID
-	GameID      int64              `bson:"game_id" json:"game_id"`                   // 游戏表ID
-	Content     string             `bson:"content" json:"content"`                   // HTML简介内容
-	Lang        string             `bson:"lang" json:"lang"`                         // 语言
-	Screenshots []string           `bson:"screenshots,omitempty" json:"screenshots"` // 截图URL数组
-	Videos      []string           `bson:"videos,omitempty" json:"videos"`           // 视频URL数组
-	CreateTime  time.Time          `bson:"create_time" json:"create_time"`           // 创建时间
-	UpdateTime  time.Time          `bson:"update_time" json:"update_time"`           // 更新时间
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`                    // MongoDB自动生成的ID
+	GameID      int64              `bson:"game_id" json:"game_id"`                     // 游戏表ID
+	Content     string             `bson:"content" json:"content"`                     // HTML简介内容
+	Lang        string             `bson:"lang" json:"lang"`                           // 语言
+	Screenshots []string           `bson:"screenshots,omitempty" json:"screenshots"`   // 截图URL数组
+	Videos      []string           `bson:"videos,omitempty" json:"videos"`             // 视频URL数组
+	ImageMetas  []ImageMeta        `bson:"image_metas,omitempty" json:"image_metas"`   // 截图缩略图等信息, 与截图顺序一致
+	HeaderImage *ImageMeta         `bson:"header_image,omitempty" json:"header_image"` // 封面图缩略图等信息
+	CreateTime  time.Time          `bson:"create_time" json:"create_time"`             // 创建时间
+	UpdateTime  time.Time          `bson:"update_time" json:"update_time"`             // 更新时间
 }
 
 // GameIntroVo 响应VO
@@ -220,6 +765,8 @@ type GameIntroVo struct {
 	Content     string       `json:"content"`
 	Screenshots []string     `json:"screenshots"`
 	Videos      []string     `json:"videos"`
+	ImageMetas  []ImageMeta  `json:"image_metas"`
+	HeaderImage *ImageMeta   `json:"header_image"`
 	UpdateTime  cm.LocalTime `json:"update_time"`
 }
 
@@ -227,3 +774,31 @@ type GameIntroVo struct {
 func (GameIntro) TableName() string {
 	return "game_intro"
 }
+
+// JobResult 一次任务执行的结果
+type JobResult struct {
+	StartTime cm.LocalTime `json:"startTime"`       // 开始时间
+	EndTime   cm.LocalTime `json:"endTime"`         // 结束时间, 执行中为空
+	Total     int64        `json:"total"`           // 采集的游戏任务数
+	Failed    int64        `json:"failed"`          // 失败的游戏任务数
+	Error     string       `json:"error,omitempty"` // 任务整体失败原因
+}
+
+// JobStatus 任务状态
+type JobStatus struct {
+	Name    string     `json:"name"`              // 任务名称, 单个游戏的任务为 <任务>:<游戏表id>
+	Running bool       `json:"running"`           // 是否正在执行
+	Current *JobResult `json:"current,omitempty"` // 正在执行的进度
+	Last    *JobResult `json:"last,omitempty"`    // 上次执行结果
+}
+
+// FailedGame 采集失败的游戏, 再次采集成功后移除, 只保存在内存中, 重启后清空
+type FailedGame struct {
+	GameID    int64        `json:"gameId,string"` // 游戏表id
+	Store     string       `json:"store"`         // 商店标识
+	Stage     string       `json:"stage"`         // 失败的采集项
+	Error     string       `json:"error"`         // 最近一次失败原因
+	Count     int64        `json:"count"`         // 连续失败次数
+	FirstTime cm.LocalTime `json:"firstTime"`     // 首次失败时间
+	LastTime  cm.LocalTime `json:"lastTime"`      // 最近失败时间
+}
@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"sync"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+)
+
+// CaptureSink 将事件保存在内存中, 用于测试和调试
+type CaptureSink struct {
+	mu     sync.Mutex
+	events []models.GfgGameEvent
+	err    error
+}
+
+func NewCaptureSink() *CaptureSink {
+	return &CaptureSink{}
+}
+
+func (s *CaptureSink) Name() string { return "capture" }
+
+func (s *CaptureSink) Send(event models.GfgGameEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+// SetError 设置后 Send 将返回该错误, 用于模拟发送失败
+func (s *CaptureSink) SetError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// Events 获取已接收的事件
+func (s *CaptureSink) Events() []models.GfgGameEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.GfgGameEvent(nil), s.events...)
+}
@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/bytedance/sonic"
+)
+
+// DiscordSink Discord 频道 webhook
+type DiscordSink struct {
+	url string
+}
+
+// Discord webhook 请求体
+type discordPayload struct {
+	Username string         `json:"username"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url,omitempty"`
+	Color       int    `json:"color"`
+}
+
+// 不同事件的卡片颜色
+var discordColor = map[string]int{
+	models.EVENT_SALE_STARTED:  0x4caf50,
+	models.EVENT_PRICE_DROPPED: 0x4caf50,
+	models.EVENT_SALE_ENDED:    0x9e9e9e,
+	models.EVENT_NEWS_POSTED:   0x2196f3,
+}
+
+func NewDiscordSink(url string) *DiscordSink {
+	return &DiscordSink{url: url}
+}
+
+func (s *DiscordSink) Name() string { return "discord" }
+
+func (s *DiscordSink) Send(event models.GfgGameEvent) error {
+	title, text := FormatEvent(event)
+	body, err := sonic.Marshal(discordPayload{
+		Username: "GoFurry",
+		Embeds: []discordEmbed{{
+			Title:       title,
+			Description: text,
+			URL:         event.URL,
+			Color:       discordColor[event.EventType],
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	return postJSON(s.url, body, nil)
+}
@@ -0,0 +1,266 @@
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	"github.com/GoFurry/gofurry-game-collector/roof/env"
+)
+
+/*
+ * @Desc: 采集事件通知
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+// Sink 通知渠道
+type Sink interface {
+	// Name 渠道名称, 用于日志
+	Name() string
+	// Send 发送一条事件, 返回错误时按配置重试
+	Send(event models.GfgGameEvent) error
+}
+
+// 订阅者, events 为空时接收全部事件
+type subscriber struct {
+	sink   Sink
+	events map[string]bool
+}
+
+// Notifier 事件通知分发器, 事件进入队列后由后台协程发送
+type Notifier struct {
+	subscribers []subscriber
+	queue       chan models.GfgGameEvent
+	retry       int
+	backoff     time.Duration
+}
+
+var defaultNotifier *Notifier
+
+// GetNotifier 获取默认分发器, 未初始化时返回 nil
+func GetNotifier() *Notifier { return defaultNotifier }
+
+// NewNotifier 创建分发器并启动发送协程
+func NewNotifier(queueSize int, retry int, backoff time.Duration) *Notifier {
+	if queueSize <= 0 {
+		queueSize = 1000
+	}
+	n := &Notifier{
+		queue:   make(chan models.GfgGameEvent, queueSize),
+		retry:   retry,
+		backoff: backoff,
+	}
+	go n.run()
+	return n
+}
+
+// InitNotifier 根据配置初始化默认分发器
+func InitNotifier() {
+	conf := env.GetServerConfig().Notifier
+	n := NewNotifier(conf.QueueSize, conf.Retry, time.Duration(conf.Backoff)*time.Second)
+	for _, v := range conf.Sinks {
+		var sink Sink
+		switch v.Type {
+		case "webhook":
+			sink = NewWebhookSink(v.URL, v.Secret)
+		case "discord":
+			sink = NewDiscordSink(v.URL)
+		case "telegram":
+			sink = NewTelegramSink(v.BotToken, v.ChatID)
+		default:
+			log.Warn("未知的通知渠道类型: ", v.Type)
+			continue
+		}
+		n.AddSink(sink, v.Events...)
+	}
+	defaultNotifier = n
+}
+
+// AddSink 添加通知渠道, events 为空时接收全部事件
+func (n *Notifier) AddSink(sink Sink, events ...string) {
+	sub := subscriber{sink: sink}
+	if len(events) > 0 {
+		sub.events = make(map[string]bool)
+		for _, v := range events {
+			sub.events[v] = true
+		}
+	}
+	n.subscribers = append(n.subscribers, sub)
+}
+
+// Dispatch 将事件放入发送队列, 队列满时丢弃
+func (n *Notifier) Dispatch(event models.GfgGameEvent) {
+	if n == nil || len(n.subscribers) == 0 {
+		return
+	}
+	select {
+	case n.queue <- event:
+	default:
+		log.Warn("通知队列已满, 丢弃事件: ", event.EventType, " game_id=", event.GameID)
+	}
+}
+
+// 后台发送
+func (n *Notifier) run() {
+	for event := range n.queue {
+		for _, sub := range n.subscribers {
+			if sub.events != nil && !sub.events[event.EventType] {
+				continue
+			}
+			n.send(sub.sink, event)
+		}
+	}
+}
+
+// 发送失败时按指数退避重试, 只重试网络错误、5xx 和 429, 其余 4xx 直接放弃
+func (n *Notifier) send(sink Sink, event models.GfgGameEvent) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error("receive notify send recover: ", err)
+		}
+	}()
+
+	backoff := n.backoff
+	for i := 0; ; i++ {
+		err := sink.Send(event)
+		if err == nil {
+			return
+		}
+		retryable, retryAfter := Retryable(err)
+		if !retryable {
+			log.Error(sink.Name(), " 通知发送失败, 不再重试: ", err)
+			return
+		}
+		if i >= n.retry {
+			log.Error(sink.Name(), " 通知发送失败: ", err)
+			return
+		}
+		// 服务端要求的等待时间比退避时间长时按服务端的等待
+		wait := max(backoff, retryAfter)
+		log.Warn(sink.Name(), " 通知发送失败, ", wait, " 后重试: ", err)
+		sleep(wait)
+		backoff *= 2
+	}
+}
+
+// 重试等待, 测试中替换
+var sleep = time.Sleep
+
+// Retry-After 的最长等待时间, 避免阻塞队列
+const maxRetryAfter = 5 * time.Minute
+
+// StatusError 非 2xx 响应
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration // 响应头 Retry-After, 没有时为 0
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("响应状态码 %d: %s", e.StatusCode, e.Body)
+}
+
+// Retryable 判断错误是否可以重试, 以及服务端要求的等待时间
+// 5xx 和 429 可以重试, 其余 4xx 为请求本身的问题, 重试也不会成功; 非响应状态码的错误视为网络错误
+func Retryable(err error) (bool, time.Duration) {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return true, 0
+	}
+	if statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500 {
+		return true, statusErr.RetryAfter
+	}
+	return false, 0
+}
+
+// 解析 Retry-After, 支持秒数和 HTTP 日期
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = time.Until(date)
+	}
+	return min(max(wait, 0), maxRetryAfter)
+}
+
+// 通知请求客户端
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// 发送 POST 请求, 非 2xx 状态码返回 *StatusError
+func postJSON(url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送POST请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Body:       string(respBody),
+		}
+	}
+	return nil
+}
+
+// 价格展示, 单位为分
+func formatPrice(price int64, currency string) string {
+	return fmt.Sprintf("%d.%02d %s", price/100, price%100, currency)
+}
+
+// FormatEvent 将事件转换为标题和正文
+func FormatEvent(event models.GfgGameEvent) (title string, text string) {
+	name := event.GameName
+	if name == "" {
+		name = fmt.Sprintf("game %d", event.GameID)
+	}
+
+	switch event.EventType {
+	case models.EVENT_SALE_STARTED:
+		title = name + " 开始打折"
+		text = fmt.Sprintf("[%s %s] %s -> %s (-%d%%)", event.Store, event.Region,
+			formatPrice(event.OldPrice, event.Currency), formatPrice(event.NewPrice, event.Currency), event.NewDiscount)
+	case models.EVENT_SALE_ENDED:
+		title = name + " 打折结束"
+		text = fmt.Sprintf("[%s %s] %s -> %s", event.Store, event.Region,
+			formatPrice(event.OldPrice, event.Currency), formatPrice(event.NewPrice, event.Currency))
+	case models.EVENT_PRICE_DROPPED:
+		title = name + " 降价"
+		text = fmt.Sprintf("[%s %s] %s -> %s", event.Store, event.Region,
+			formatPrice(event.OldPrice, event.Currency), formatPrice(event.NewPrice, event.Currency))
+	case models.EVENT_NEWS_POSTED:
+		title = name + " 发布新公告"
+		text = event.Detail
+	case models.EVENT_RELEASE_DATE_CHANGED:
+		title = name + " 发行日期变化"
+		text = event.Detail
+	default:
+		title = name + " " + event.EventType
+		text = event.Detail
+	}
+	return
+}
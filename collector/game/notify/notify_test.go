@@ -0,0 +1,278 @@
+package notify
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/tidwall/gjson"
+)
+
+var testEvent = models.GfgGameEvent{
+	GameID:      1001,
+	Store:       "steam",
+	Region:      "US",
+	GameName:    "Furry Adventure",
+	EventType:   models.EVENT_SALE_STARTED,
+	Currency:    "USD",
+	OldPrice:    1999,
+	NewPrice:    999,
+	NewDiscount: 50,
+	URL:         "https://store.steampowered.com/app/1599600/",
+}
+
+// 记录收到的请求, 按 statuses 依次返回状态码, 用完后返回 200
+type recordServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+}
+
+func newRecordServer(t *testing.T, statuses []int, headers map[string]string) *recordServer {
+	t.Helper()
+	s := &recordServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		i := len(s.requests)
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, string(body))
+		s.mu.Unlock()
+		if i < len(statuses) {
+			for k, v := range headers {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(statuses[i])
+			w.Write([]byte("error"))
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *recordServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// 替换重试等待, 记录每次等待时间
+func stubSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var waits []time.Duration
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	t.Cleanup(func() { sleep = time.Sleep })
+	return &waits
+}
+
+func TestSign(t *testing.T) {
+	got := Sign("secret", "1700000000", []byte(`{"event":"sale_started"}`))
+	if want := "8452a60d9b7f299fb823e5b3124257499b4a207fe98eb76d403d7d8cac216573"; got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	server := newRecordServer(t, nil, nil)
+	if err := NewWebhookSink(server.URL, "secret").Send(testEvent); err != nil {
+		t.Fatal(err)
+	}
+	req, body := server.requests[0], server.bodies[0]
+	if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	timestamp := req.Header.Get("X-GF-Timestamp")
+	if want := "sha256=" + Sign("secret", timestamp, []byte(body)); req.Header.Get("X-GF-Signature") != want {
+		t.Errorf("X-GF-Signature = %q, want %q", req.Header.Get("X-GF-Signature"), want)
+	}
+	if gjson.Get(body, "event").String() != models.EVENT_SALE_STARTED || gjson.Get(body, "timestamp").String() != timestamp {
+		t.Errorf("body = %s", body)
+	}
+	if gjson.Get(body, "data.gameId").String() != "1001" || gjson.Get(body, "data.newPrice").Int() != 999 {
+		t.Errorf("data = %s", gjson.Get(body, "data").Raw)
+	}
+
+	// 没有 secret 时不签名
+	if err := NewWebhookSink(server.URL, "").Send(testEvent); err != nil {
+		t.Fatal(err)
+	}
+	if sig := server.requests[1].Header.Get("X-GF-Signature"); sig != "" {
+		t.Errorf("X-GF-Signature = %q", sig)
+	}
+}
+
+func TestDiscordSink(t *testing.T) {
+	server := newRecordServer(t, nil, nil)
+	if err := NewDiscordSink(server.URL).Send(testEvent); err != nil {
+		t.Fatal(err)
+	}
+	body := server.bodies[0]
+	embed := gjson.Get(body, "embeds.0")
+	if gjson.Get(body, "username").String() != "GoFurry" || gjson.Get(body, "embeds.#").Int() != 1 {
+		t.Errorf("body = %s", body)
+	}
+	if embed.Get("title").String() != "Furry Adventure 开始打折" ||
+		embed.Get("description").String() != "[steam US] 19.99 USD -> 9.99 USD (-50%)" ||
+		embed.Get("url").String() != testEvent.URL ||
+		embed.Get("color").Int() != 0x4caf50 {
+		t.Errorf("embed = %s", embed.Raw)
+	}
+}
+
+func TestTelegramSink(t *testing.T) {
+	server := newRecordServer(t, nil, nil)
+	sink := NewTelegramSink("123:ABC", "-100200")
+	sink.apiBase = server.URL
+	if err := sink.Send(testEvent); err != nil {
+		t.Fatal(err)
+	}
+	if path := server.requests[0].URL.Path; path != "/bot123:ABC/sendMessage" {
+		t.Errorf("path = %q", path)
+	}
+	body := server.bodies[0]
+	want := "Furry Adventure 开始打折\n[steam US] 19.99 USD -> 9.99 USD (-50%)\n" + testEvent.URL
+	if gjson.Get(body, "chat_id").String() != "-100200" || gjson.Get(body, "text").String() != want {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestTelegramRedactToken(t *testing.T) {
+	// 服务已关闭, 请求返回带完整地址的网络错误
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	sink := NewTelegramSink("123:SECRET", "1")
+	sink.apiBase = server.URL
+	err := sink.Send(testEvent)
+	if err == nil {
+		t.Fatal("请求已关闭的服务应失败")
+	}
+	if strings.Contains(err.Error(), "SECRET") || !strings.Contains(err.Error(), "<bot-token>") {
+		t.Errorf("err = %v", err)
+	}
+	if ok, _ := Retryable(err); !ok {
+		t.Error("网络错误应重试")
+	}
+
+	// 状态码错误保留类型
+	statusServer := newRecordServer(t, []int{http.StatusBadRequest}, nil)
+	sink.apiBase = statusServer.URL
+	var statusErr *StatusError
+	if err = sink.Send(testEvent); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	cases := []struct {
+		err   error
+		retry bool
+		wait  time.Duration
+	}{
+		{errors.New("connection refused"), true, 0},
+		{&StatusError{StatusCode: 500}, true, 0},
+		{&StatusError{StatusCode: 503, RetryAfter: time.Second}, true, time.Second},
+		{&StatusError{StatusCode: 429, RetryAfter: 3 * time.Second}, true, 3 * time.Second},
+		{&StatusError{StatusCode: 400}, false, 0},
+		{&StatusError{StatusCode: 401}, false, 0},
+		{&StatusError{StatusCode: 404}, false, 0},
+	}
+	for _, c := range cases {
+		if retry, wait := Retryable(c.err); retry != c.retry || wait != c.wait {
+			t.Errorf("Retryable(%v) = %v %v, want %v %v", c.err, retry, wait, c.retry, c.wait)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("7"); got != 7*time.Second {
+		t.Errorf("seconds = %v", got)
+	}
+	if got := parseRetryAfter(""); got != 0 {
+		t.Errorf("empty = %v", got)
+	}
+	if got := parseRetryAfter("-5"); got != 0 {
+		t.Errorf("negative = %v", got)
+	}
+	if got := parseRetryAfter("86400"); got != maxRetryAfter {
+		t.Errorf("too long = %v", got)
+	}
+	date := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got < 25*time.Second || got > 30*time.Second {
+		t.Errorf("date = %v", got)
+	}
+}
+
+func TestSendRetry(t *testing.T) {
+	// 5xx 按指数退避重试直到成功
+	waits := stubSleep(t)
+	server := newRecordServer(t, []int{502, 503}, nil)
+	n := &Notifier{retry: 3, backoff: 10 * time.Millisecond}
+	n.send(NewWebhookSink(server.URL, ""), testEvent)
+	if server.count() != 3 {
+		t.Errorf("5xx 请求次数 = %d, want 3", server.count())
+	}
+	if len(*waits) != 2 || (*waits)[0] != 10*time.Millisecond || (*waits)[1] != 20*time.Millisecond {
+		t.Errorf("5xx waits = %v", *waits)
+	}
+
+	// 429 按 Retry-After 等待
+	waits = stubSleep(t)
+	server = newRecordServer(t, []int{429}, map[string]string{"Retry-After": "2"})
+	n.send(NewWebhookSink(server.URL, ""), testEvent)
+	if server.count() != 2 || len(*waits) != 1 || (*waits)[0] != 2*time.Second {
+		t.Errorf("429 请求次数 = %d, waits = %v", server.count(), *waits)
+	}
+
+	// 其余 4xx 不重试
+	waits = stubSleep(t)
+	server = newRecordServer(t, []int{400, 400}, nil)
+	n.send(NewWebhookSink(server.URL, ""), testEvent)
+	if server.count() != 1 || len(*waits) != 0 {
+		t.Errorf("4xx 请求次数 = %d, waits = %v", server.count(), *waits)
+	}
+
+	// 超过重试次数后放弃
+	waits = stubSleep(t)
+	sink := NewCaptureSink()
+	sink.SetError(errors.New("connection reset"))
+	n.send(sink, testEvent)
+	if want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond}; len(*waits) != len(want) ||
+		(*waits)[0] != want[0] || (*waits)[1] != want[1] || (*waits)[2] != want[2] {
+		t.Errorf("waits = %v, want %v", *waits, want)
+	}
+	if len(sink.Events()) != 0 {
+		t.Errorf("events = %v", sink.Events())
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	n := NewNotifier(10, 0, time.Millisecond)
+	all := NewCaptureSink()
+	news := NewCaptureSink()
+	n.AddSink(all)
+	n.AddSink(news, models.EVENT_NEWS_POSTED)
+
+	n.Dispatch(testEvent)
+	newsEvent := testEvent
+	newsEvent.EventType = models.EVENT_NEWS_POSTED
+	n.Dispatch(newsEvent)
+
+	deadline := time.Now().Add(time.Second)
+	for (len(all.Events()) < 2 || len(news.Events()) < 1) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if len(all.Events()) != 2 {
+		t.Fatalf("all events = %d", len(all.Events()))
+	}
+	// 按订阅的事件类型过滤
+	if events := news.Events(); len(events) != 1 || events[0].EventType != models.EVENT_NEWS_POSTED {
+		t.Errorf("news events = %v", events)
+	}
+}
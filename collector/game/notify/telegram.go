@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/bytedance/sonic"
+)
+
+// Telegram Bot API 地址
+const telegramAPIBase = "https://api.telegram.org"
+
+// TelegramSink Telegram 机器人消息
+type TelegramSink struct {
+	apiBase  string
+	botToken string
+	chatID   string
+}
+
+// sendMessage 请求体
+type telegramPayload struct {
+	ChatID string `json:"chat_id"`
+	Text   string `json:"text"`
+}
+
+func NewTelegramSink(botToken string, chatID string) *TelegramSink {
+	return &TelegramSink{
+		apiBase:  telegramAPIBase,
+		botToken: botToken,
+		chatID:   chatID,
+	}
+}
+
+func (s *TelegramSink) Name() string { return "telegram" }
+
+func (s *TelegramSink) Send(event models.GfgGameEvent) error {
+	title, text := FormatEvent(event)
+	message := title + "\n" + text
+	if event.URL != "" {
+		message += "\n" + event.URL
+	}
+	body, err := sonic.Marshal(telegramPayload{ChatID: s.chatID, Text: message})
+	if err != nil {
+		return err
+	}
+	return s.redact(postJSON(s.apiBase+"/bot"+s.botToken+"/sendMessage", body, nil))
+}
+
+// 请求地址中含有 bot token, 网络错误会带上完整地址, 返回前去掉 token 避免写入日志
+func (s *TelegramSink) redact(err error) error {
+	if err == nil || s.botToken == "" || !strings.Contains(err.Error(), s.botToken) {
+		return err
+	}
+	return errors.New(strings.ReplaceAll(err.Error(), s.botToken, "<bot-token>"))
+}
@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/bytedance/sonic"
+)
+
+// WebhookSink 通用 HTTP 回调
+// 签名为 HMAC-SHA256(secret, timestamp + "." + body), 通过 X-GF-Timestamp 和 X-GF-Signature 请求头传递
+type WebhookSink struct {
+	url    string
+	secret string
+}
+
+// webhook 请求体
+type webhookPayload struct {
+	Event     string              `json:"event"`
+	Timestamp int64               `json:"timestamp"`
+	Data      models.GfgGameEvent `json:"data"`
+}
+
+func NewWebhookSink(url string, secret string) *WebhookSink {
+	return &WebhookSink{url: url, secret: secret}
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func (s *WebhookSink) Send(event models.GfgGameEvent) error {
+	timestamp := time.Now().Unix()
+	body, err := sonic.Marshal(webhookPayload{
+		Event:     event.EventType,
+		Timestamp: timestamp,
+		Data:      event,
+	})
+	if err != nil {
+		return err
+	}
+
+	timestampStr := strconv.FormatInt(timestamp, 10)
+	headers := map[string]string{"X-GF-Timestamp": timestampStr}
+	if s.secret != "" {
+		headers["X-GF-Signature"] = "sha256=" + Sign(s.secret, timestampStr, body)
+	}
+	return postJSON(s.url, body, headers)
+}
+
+// Sign 计算 webhook 签名, 接收方可用同样方式校验
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/GoFurry/gofurry-game-collector/roof/env"
+	"github.com/bytedance/sonic"
+)
+
+// 游戏简介字段长度
+const gameInfoMaxLen = 300
+
+// DiscoverGames 按配置的标签和关键词搜索商店, 新发现的游戏记为待审核的候选游戏
+func (s gameService) DiscoverGames() {
+	if gfErr := s.runJob(JOB_DISCOVERY, 0, false); gfErr != nil {
+		log.Warn("DiscoverGames 跳过: ", gfErr.GetMsg())
+	}
+}
+
+// 搜索候选游戏, 不使用游戏列表
+func (s gameService) discoverGames(run *jobRun, _ []models.GameID) {
+	conf := env.GetServerConfig().Collector.Game.Discovery
+	maxPages := conf.MaxPages
+	if maxPages <= 0 {
+		maxPages = 10
+	}
+
+	// 已添加的游戏不再作为候选
+	appidList, gfErr := dao.GetGameDao().GetGameAppidList()
+	if gfErr != nil {
+		log.Error("DiscoverGames 获取游戏 appid 失败: ", gfErr.GetMsg())
+		run.setError(gfErr.GetMsg())
+		return
+	}
+	exist := make(map[int64]bool, len(appidList))
+	for _, v := range appidList {
+		exist[v] = true
+	}
+
+	log.Info("Game Discovery 搜索开始")
+	var searcher GameSearcher = steamSource{}
+	candidates := make(map[int64]*models.GfgGameCandidate)
+	var order []int64
+	search := func(tagID int64, keyword string, matched string) {
+		resList, err := searcher.SearchGames(tagID, keyword, maxPages)
+		if err != nil {
+			log.Warn("搜索 ", matched, " 失败: ", err.GetMsg())
+			run.setError("搜索 " + matched + " 失败: " + err.GetMsg())
+		}
+		for _, v := range resList {
+			if exist[v.Appid] {
+				continue
+			}
+			// 同一游戏被多个条件命中时记录全部条件
+			if c, ok := candidates[v.Appid]; ok {
+				if !strings.Contains(","+c.Matched+",", ","+matched+",") {
+					c.Matched += "," + matched
+				}
+				continue
+			}
+			candidates[v.Appid] = &models.GfgGameCandidate{
+				ID:            util.GenerateId(),
+				Appid:         v.Appid,
+				Name:          v.Name,
+				ReleaseDate:   v.ReleaseDate,
+				HeaderImage:   v.HeaderImage,
+				Price:         v.Price,
+				ReviewSummary: v.ReviewSummary,
+				Matched:       matched,
+				Status:        models.CANDIDATE_PENDING,
+				CreateTime:    cm.LocalTime(time.Now()),
+			}
+			order = append(order, v.Appid)
+		}
+	}
+	for _, tagID := range conf.Tags {
+		search(tagID, "", "tag:"+util.Int642String(tagID))
+	}
+	for _, keyword := range conf.Keywords {
+		search(0, keyword, "keyword:"+keyword)
+	}
+
+	list := make([]models.GfgGameCandidate, 0, len(order))
+	for _, appid := range order {
+		list = append(list, *candidates[appid])
+	}
+	// 已是候选游戏的 appid 在插入时跳过, 保留原审核状态
+	cnt, gfErr := dao.GetGameCandidateDao().AddCandidates(list)
+	if gfErr != nil {
+		log.Error("DiscoverGames 保存候选游戏失败: ", gfErr.GetMsg())
+		run.setError(gfErr.GetMsg())
+		return
+	}
+	log.Info("Game Discovery 搜索结束, 搜索到 ", len(list), " 个未添加的游戏, 新增候选 ", cnt, " 个")
+}
+
+// ApproveCandidate 通过候选游戏, 采集商店详情并创建游戏记录, 返回游戏表id
+func (s gameService) ApproveCandidate(appid int64) (int64, common.GFError) {
+	candidate, gfErr := dao.GetGameCandidateDao().GetCandidateByAppid(appid)
+	if gfErr != nil {
+		return 0, gfErr
+	}
+	if candidate.Status == models.CANDIDATE_APPROVED {
+		return candidate.GameID, common.NewServiceError("候选游戏已通过审核")
+	}
+
+	detailMap, gfErr := steamSource{}.FetchDetails(models.GameID{Appid: appid})
+	if gfErr != nil {
+		return 0, gfErr
+	}
+	if len(detailMap) == 0 {
+		return 0, common.NewServiceError("未采集到游戏详情")
+	}
+
+	// 中文和英文分别取对应语言的详情, 缺失时使用其他语言
+	var zh, en models.StoreDetail
+	for _, lang := range util.SortedKeys(detailMap) {
+		if zh.Name == "" {
+			zh = detailMap[lang]
+		}
+		if en.Name == "" {
+			en = detailMap[lang]
+		}
+	}
+	if v, ok := detailMap["zh"]; ok {
+		zh = v
+	}
+	if v, ok := detailMap["en"]; ok {
+		en = v
+	}
+
+	developers, _ := sonic.MarshalString(nonNilStrings(en.Developers))
+	publishers, _ := sonic.MarshalString(nonNilStrings(en.Publishers))
+	game := models.GfgGame{
+		ID:          util.GenerateId(),
+		Name:        zh.Name,
+		NameEn:      en.Name,
+		Info:        truncateRunes(zh.ShortDescription, gameInfoMaxLen),
+		InfoEn:      truncateRunes(en.ShortDescription, gameInfoMaxLen),
+		ReleaseDate: en.ReleaseDate.Date,
+		Developers:  developers,
+		Publishers:  publishers,
+		Appid:       appid,
+		Header:      en.HeaderImage,
+	}
+	if gfErr = dao.GetGameCandidateDao().ApproveCandidate(candidate.ID, &game); gfErr != nil {
+		return 0, gfErr
+	}
+	return game.ID, nil
+}
+
+// RejectCandidate 拒绝候选游戏, 之后的搜索不会再次加入
+func (s gameService) RejectCandidate(appid int64) common.GFError {
+	candidate, gfErr := dao.GetGameCandidateDao().GetCandidateByAppid(appid)
+	if gfErr != nil {
+		return gfErr
+	}
+	if candidate.Status == models.CANDIDATE_APPROVED {
+		return common.NewServiceError("候选游戏已通过审核")
+	}
+	return dao.GetGameCandidateDao().RejectCandidate(candidate.ID)
+}
+
+// 按字符截断
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// json 序列化时空列表输出 [] 而不是 null
+func nonNilStrings(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/notify"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	cs "github.com/GoFurry/gofurry-game-collector/common/service"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/bytedance/sonic"
+)
+
+// 采集器事件发布的 redis 频道
+const EVENT_CHANNEL = "game:events"
+
+// 记录事件, 发布到 redis 频道并推送通知
+func emitEvent(event models.GfgGameEvent) {
+	event.ID = util.GenerateId()
+	if event.CreateTime.IsZero() {
+		event.CreateTime = cm.LocalTime(time.Now())
+	}
+	if event.GameName == "" {
+		event.GameName, _ = dao.GetGameDao().GetGameName(event.GameID)
+	}
+
+	// 存数据库
+	if err := dao.GetGameEventDao().Add(&event); err != nil {
+		log.Error("add GfgGameEvent error: ", err.GetMsg())
+	}
+
+	// 发布到 redis
+	jsonResult, _ := sonic.Marshal(event)
+	cs.Publish(EVENT_CHANNEL, string(jsonResult))
+
+	// 推送通知
+	notify.GetNotifier().Dispatch(event)
+}
+
+// 根据前后折扣判断打折开始或结束, 其他情况下价格降低视为降价
+func detectSaleEvent(last models.GfgGamePriceHistory, record models.GfgGamePrice) {
+	var eventType string
+	switch {
+	case last.Discount == 0 && record.Discount > 0:
+		eventType = models.EVENT_SALE_STARTED
+	case last.Discount > 0 && record.Discount == 0:
+		eventType = models.EVENT_SALE_ENDED
+	case last.Currency == record.Currency && record.Final < last.Final:
+		eventType = models.EVENT_PRICE_DROPPED
+	default:
+		return
+	}
+
+	emitEvent(models.GfgGameEvent{
+		GameID:      record.GameID,
+		Store:       record.Store,
+		Region:      record.Region,
+		EventType:   eventType,
+		Currency:    record.Currency,
+		OldPrice:    last.Final,
+		NewPrice:    record.Final,
+		OldDiscount: last.Discount,
+		NewDiscount: record.Discount,
+		CreateTime:  record.UpdateTime,
+	})
+}
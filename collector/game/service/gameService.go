@@ -1,7 +1,7 @@
 package service
 
 import (
-	"context"
+	"fmt"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -17,7 +17,6 @@ import (
 	"github.com/GoFurry/gofurry-game-collector/roof/env"
 	"github.com/bytedance/sonic"
 	"github.com/sourcegraph/conc/pool"
-	"github.com/tidwall/gjson"
 	"golang.org/x/time/rate"
 )
 
@@ -29,9 +28,8 @@ func GetGameService() *gameService { return gameSingleton }
 
 var gameThread *pool.Pool
 var gameRWLock sync.RWMutex
-var wg sync.WaitGroup
 
-var steamAPILimiter, steamStoreLimiter *rate.Limiter
+var steamAPILimiter, steamStoreLimiter, itchLimiter, gogLimiter *rate.Limiter
 
 var storeReqCount atomic.Int32
 
@@ -43,99 +41,130 @@ func InitLimiter() {
 
 	limiter := env.GetServerConfig().Collector.Limiter
 	// api 接口限流器 Steam风控大概在 100 token / 1 minutes
-	steamAPILimiter = rate.NewLimiter(rate.Every(time.Duration(limiter.SteamApi)*time.Second), 3)
+	steamAPILimiter = rate.NewLimiter(limiterEvery(limiter.SteamApi, 2), 3)
 	// store 接口限流器 Steam风控大概在 [150,250]token / 5 minutes
-	steamStoreLimiter = rate.NewLimiter(rate.Every(time.Duration(limiter.SteamStore)*time.Second), 3)
+	steamStoreLimiter = rate.NewLimiter(limiterEvery(limiter.SteamStore, 6), 3)
+	// itch.io 限流器
+	itchLimiter = rate.NewLimiter(limiterEvery(limiter.Itch, 2), 3)
+	// gog 限流器
+	gogLimiter = rate.NewLimiter(limiterEvery(limiter.Gog, 2), 3)
 }
 
-// 设置请求头, 明确指定语言为中文
-var headersMap = map[string]string{
-	"User-Agent":      common.USER_AGENT,
-	"Accept-Language": common.ACCEPT_LANGUAGE_CN,
+// 一个令牌的间隔秒数, 未配置或不大于 0 时使用默认值, 避免 rate.Every(0) 不限流
+func limiterEvery(seconds int, defaultSeconds int) rate.Limit {
+	if seconds <= 0 {
+		seconds = defaultSeconds
+	}
+	return rate.Every(time.Duration(seconds) * time.Second)
+}
+
+// 未配置 collector.game.languages 时默认采集的语言
+var defaultLanguageList = []env.LanguageConfig{
+	{Code: "schinese", Lang: "zh", CC: "CN", AcceptLanguage: common.ACCEPT_LANGUAGE_CN},
+	{Code: "english", Lang: "en", CC: "US", AcceptLanguage: common.ACCEPT_LANGUAGE_EN},
 }
 
-// 采集的国区代码
-var langList = []string{"CN", "HK", "US"}
+// 获取采集详情的语言
+func getLanguageList() []env.LanguageConfig {
+	if languages := env.GetServerConfig().Collector.Game.Languages; len(languages) > 0 {
+		return languages
+	}
+	return defaultLanguageList
+}
 
 // Collect 游戏模块采集部分
 func (s gameService) Collect() {
-	// 每次采集都查寻数据库 保证热更新
-	gameList, err := addAllGameToList()
-	if err != nil {
-		log.Error("receive InitGameCollection recover: ", err)
+	if gfErr := s.runJob(JOB_COLLECT, 0, false); gfErr != nil {
+		log.Warn("Collect 跳过: ", gfErr.GetMsg())
 	}
+}
 
+// 采集游戏详情、公告和评测汇总
+func (s gameService) collectGames(run *jobRun, gameList []models.GameID) {
 	log.Info("Game Collect 采集开始")
-	// 遍历 Game 列表
-	// 游戏信息
-	for _, v := range gameList {
-		wg.Add(1)
-		gameThread.Go(func() {
-			if err := steamStoreLimiter.Wait(context.Background()); err != nil {
-				log.Error("获取限流令牌失败: ", err)
-				wg.Done()
-				return
+	// 遍历数据源和各自负责的 Game 列表
+	for _, src := range GetSourceList() {
+		srcGameList := filterGameList(src, gameList)
+		// 游戏信息
+		for _, v := range srcGameList {
+			run.goGame(startGameCollect(run, src, v)) // 执行实际采集逻辑
+		}
+		// 游戏更新信息
+		for _, v := range srcGameList {
+			run.goGame(startGameNewsCollect(run, src, v)) // 执行实际采集逻辑
+		}
+		// 游戏评测汇总
+		if _, ok := src.(ReviewFetcher); ok {
+			for _, v := range srcGameList {
+				run.goGame(startGameReviewCollect(run, src, v)) // 执行实际采集逻辑
 			}
-			startGameCollect(v)() // 执行实际采集逻辑
-		})
-	}
-	// 游戏更新信息
-	for _, v := range gameList {
-		wg.Add(1)
-		gameThread.Go(func() {
-			if err := steamStoreLimiter.Wait(context.Background()); err != nil {
-				log.Error("获取限流令牌失败: ", err)
-				wg.Done()
-				return
-			}
-			startGameNewsCollect(v)() // 执行实际采集逻辑
-		})
+		}
 	}
 	// 等待所有 Game 采集完毕
-	wg.Wait()
+	run.wg.Wait()
 	log.Info("Game Collect 采集结束")
+}
 
+// 只采集公告
+func (s gameService) collectNews(run *jobRun, gameList []models.GameID) {
+	log.Info("Game News 采集开始")
+	for _, src := range GetSourceList() {
+		for _, v := range filterGameList(src, gameList) {
+			run.goGame(startGameNewsCollect(run, src, v)) // 执行实际采集逻辑
+		}
+	}
+	// 等待所有 Game 采集完毕
+	run.wg.Wait()
+	log.Info("Game News 采集结束")
 }
 
 // 游戏在线人数采集部分
 func (s gameService) CollectCurrentPlayers() {
-	// 每次采集都查寻数据库 保证热更新
-	gameList, err := addAllGameToList()
-	if err != nil {
-		log.Error("receive InitGameCollection recover: ", err)
+	if gfErr := s.runJob(JOB_PLAYERS, 0, false); gfErr != nil {
+		log.Warn("CollectCurrentPlayers 跳过: ", gfErr.GetMsg())
 	}
+}
 
+// 采集在线人数, 全部游戏采集完成后汇总
+func (s gameService) collectPlayers(run *jobRun, gameList []models.GameID) {
 	log.Info("CollectCurrentPlayers 采集开始")
-	// 遍历 Game 列表
-	// 游戏信息
-	for _, v := range gameList {
-		wg.Add(1)
-		gameThread.Go(func() {
-			if err := steamAPILimiter.Wait(context.Background()); err != nil {
-				log.Error("获取限流令牌失败: ", err)
-				wg.Done()
-				return
-			}
-			startGamePlayerCollect(v)() // 执行实际采集逻辑
-		})
+	// 遍历数据源和各自负责的 Game 列表
+	for _, src := range GetSourceList() {
+		for _, v := range filterGameList(src, gameList) {
+			run.goGame(startGamePlayerCollect(run, src, v)) // 执行实际采集逻辑
+		}
 	}
 	// 等待所有 Game 采集完毕
-	wg.Wait()
+	run.wg.Wait()
 	log.Info("CollectCurrentPlayers 采集结束")
+
+	// 汇总在线人数, 单个游戏的采集不触发
+	if !run.single {
+		s.RollupPlayerCount()
+	}
 }
 
 // startGamePlayerCollect 开始游戏在线人数采集
-func startGamePlayerCollect(gameID models.GameID) func() {
+func startGamePlayerCollect(run *jobRun, src Source, gameID models.GameID) func() {
 	return func() {
+		defer run.done() // 确保线程结束时组数减少
 		defer func() {
 			if err := recover(); err != nil {
 				log.Error("receive startGamePlayerCollect recover: ", err)
+				run.fail(STAGE_PLAYERS, src.Name(), gameID, fmt.Sprint(err))
 			}
 		}()
-		defer wg.Done() // 确保线程结束时组数减少
 
 		// 执行采集获取结果
-		playerCount := performGamePlayerCollect(gameID)
+		playerCount, gfErr := src.FetchPlayerCount(gameID)
+		if isNotSupported(gfErr) {
+			return
+		} else if gfErr != nil {
+			log.Warn(src.Name(), " FetchPlayerCount 失败, game_id=", gameID.ID, " err:", gfErr.GetMsg())
+			run.fail(STAGE_PLAYERS, src.Name(), gameID, gfErr.GetMsg())
+			return
+		}
+		run.succeed(STAGE_PLAYERS, src.Name(), gameID)
 
 		countSaveRecord := models.GfgGamePlayerCount{
 			ID:         util.GenerateId(),
@@ -144,21 +173,9 @@ func startGamePlayerCollect(gameID models.GameID) func() {
 			CreateTime: cm.LocalTime(time.Now()),
 		}
 
-		// 存数据库
-		skipCount := 120
-		cnt, err := dao.GetGamePlayerDao().GetPlayerCountByID(gameID.ID)
-		if err != nil {
-			log.Error("GetPlayerCountByID error: ", err)
-		}
-		last, err := dao.GetGamePlayerDao().GetLastRecordByID(gameID.ID, skipCount)
-		if err != nil {
-			log.Error("GetLastRecordByID error: ", err)
-		}
-		if cnt >= int64(skipCount) {
-			dao.GetGamePlayerDao().Delete(last, models.GfgGamePlayerCount{}) // 删最早的记录
-			dao.GetGamePlayerDao().Add(&countSaveRecord)                     // 入库
-		} else {
-			dao.GetGamePlayerDao().Add(&countSaveRecord) // 入库
+		// 存数据库, 长期数据由 RollupPlayerCount 汇总
+		if err := dao.GetGamePlayerDao().Add(&countSaveRecord); err != nil {
+			log.Error("add GfgGamePlayerCount error: ", err.GetMsg())
 		}
 
 		// 存 redis
@@ -170,636 +187,193 @@ func startGamePlayerCollect(gameID models.GameID) func() {
 	}
 }
 
-// performGamePlayerCollect 执行游戏在线人数采集
-func performGamePlayerCollect(gameID models.GameID) int64 {
-	defer func() {
-		if err := recover(); err != nil {
-			log.Error("receive performGamePlayerCollect recover: ", err)
-		}
-	}()
-
-	appidStr := util.Int642String(gameID.Appid)
-
-	// 请求地址
-	url := `https://api.steampowered.com/ISteamUserStats/GetNumberOfCurrentPlayers/v1/?`
-
-	// 设置参数
-	paramsMap := map[string]string{
-		"appid": appidStr,
-	}
-
-	// 请求 SteamAPI
-	respDataStr, httpErr := util.GetByHttpWithParams(url, headersMap, paramsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
-	if httpErr != nil {
-		log.Warn(httpErr)
-		return 0
-	}
-
-	return gjson.Get(respDataStr, "response.player_count").Int()
-}
-
 // 开始游戏记录采集
-func startGameCollect(gameID models.GameID) func() {
+func startGameCollect(run *jobRun, src Source, gameID models.GameID) func() {
 	return func() {
+		defer run.done() // 确保线程结束时组数减少
 		defer func() {
 			if err := recover(); err != nil {
 				log.Error("receive startGameCollect recover, game_id=", gameID.ID, " appid=", gameID.Appid, " err:", err)
+				run.fail(STAGE_DETAIL, src.Name(), gameID, fmt.Sprint(err))
 			}
 		}()
-		defer wg.Done() // 确保线程结束时组数减少
 
 		// 执行采集获取结果
-		priceRes, infoRes := performGameCollect(gameID)
-
-		// 存储结构
-		var dbRecordCN, dbRecordEN models.GfgGameRecord
-		var redisRecordCN, redisRecordEN models.GameSaveModel
-		var priceList []models.PriceModel
-
-		dbRecordCN.ID, dbRecordEN.ID = util.GenerateId(), util.GenerateId()
-		dbRecordCN.GameID, dbRecordEN.GameID = gameID.ID, gameID.ID
-		dbRecordCN.Lang, dbRecordEN.Lang = "zh", "en"
-
-		// 处理价格结果
-		if _, exist := priceRes["free"]; exist {
-			priceList = append(priceList, models.PriceModel{
-				Price:   "free",
-				Country: "免费",
-			})
-			dbRecordCN.Initial, dbRecordEN.Initial = 0, 0
-			dbRecordCN.Final, dbRecordEN.Final = 0, 0
-			dbRecordCN.Discount, dbRecordEN.Discount = 0, 0
-			redisRecordCN.Price.Initial, redisRecordCN.Price.Final, redisRecordCN.Price.DiscountPercent,
-				redisRecordCN.Price.InitialFormatted, redisRecordCN.Price.FinalFormatted = 0, 0, 0, "免费", "免费"
-
-			redisRecordEN.Price.Initial, redisRecordEN.Price.Final, redisRecordEN.Price.DiscountPercent,
-				redisRecordEN.Price.InitialFormatted, redisRecordEN.Price.FinalFormatted = 0, 0, 0, "free", "free"
-
-			redisRecordCN.Price.Currency, redisRecordEN.Price.Currency = "CNY", "USD"
-		} else {
-			for k, v := range priceRes {
-				priceList = append(priceList, models.PriceModel{
-					Price:   v.FinalFormatted,
-					Country: k,
-				})
-				switch k {
-				case "CN":
-					dbRecordCN.Initial = v.Initial
-					dbRecordCN.Final = v.Final
-					dbRecordCN.Discount = v.DiscountPercent
-
-					redisRecordCN.Price = v
-				case "US":
-					dbRecordEN.Initial = v.Initial
-					dbRecordEN.Final = v.Final
-					dbRecordEN.Discount = v.DiscountPercent
-
-					redisRecordEN.Price = v
-				}
-			}
-		}
-		priceListJson, jsonErr := sonic.Marshal(priceList)
-		if jsonErr != nil {
-			log.Error("marshal priceList error: ", jsonErr)
-		}
-		priceListStr := string(priceListJson)
-		dbRecordCN.PriceList, dbRecordEN.PriceList, redisRecordCN.PriceList, redisRecordEN.PriceList = priceListStr, priceListStr, priceListStr, priceListStr
-
-		// 处理其他信息
-		for k, v := range infoRes {
-			switch k {
-			case "CN":
-				// 数据库部分
-				dbRecordCN.Language = v["supported_languages"].(string)               // 支持的语言
-				dbRecordCN.Developer = strings.Join(v["developers"].([]string), ", ") // 开发商
-				dbRecordCN.Publisher = strings.Join(v["publishers"].([]string), ", ") // 发行商
-				dbRecordCN.Cover = v["header_image"].(string)                         // 封面图
-				dbRecordCN.Info = v["short_description"].(string)                     // 概述
-				// 发行时间
-				if v["release_date"].(models.SteamAppRelease).ComingSoon {
-					dbRecordCN.ReleaseDate = "即将推出"
-				} else {
-					dbRecordCN.ReleaseDate = v["release_date"].(models.SteamAppRelease).Date
-				}
-				// 支持平台
-				platform := v["platforms"].(models.SteamAppPlatform)
-				var platforms []string
-				if platform.Windows {
-					platforms = append(platforms, "windows")
-				}
-				if platform.Mac {
-					platforms = append(platforms, "mac")
-				}
-				if platform.Linux {
-					platforms = append(platforms, "linux")
-				}
-				dbRecordCN.Platform = strings.Join(platforms, ", ")
-				// redis 部分
-				redisRecordCN.Support = v["support_info"].(models.SteamAppSupport)              // 开发商联系方式
-				redisRecordCN.Screenshots = v["screenshots"].([]models.SteamAppScreenshot)      // 游戏图片
-				redisRecordCN.Movies = v["movies"].([]models.SteamAppMovie)                     // 游戏视频
-				redisRecordCN.SupportedLanguages = dbRecordCN.Language                          // 支持语言
-				redisRecordCN.Developers = dbRecordCN.Developer                                 // 开发商
-				redisRecordCN.Publishers = dbRecordCN.Publisher                                 // 发行商
-				redisRecordCN.HeaderImage = dbRecordCN.Cover                                    // 封面图
-				redisRecordCN.ShortDescription = dbRecordCN.Info                                // 概述
-				redisRecordCN.Date = dbRecordCN.ReleaseDate                                     // 发行日期
-				redisRecordCN.Platforms = dbRecordCN.Platform                                   // 支持平台
-				redisRecordCN.RequiredAge = v["required_age"].(string)                          // 年龄限制
-				redisRecordCN.Website = v["website"].(string)                                   // 游戏官网
-				redisRecordCN.ContentDescriptors = v["content_descriptors"].(string)            // 内容描述
-				redisRecordCN.CollectDate = cm.LocalTime(time.Now())                            // 采集时间
-				redisRecordCN.DetailedDescription = v["detailed_description"].(string)          // 详情简介
-				redisRecordCN.AboutTheGame = v["about_the_game"].(string)                       // 关于游戏
-				redisRecordCN.PcRequirements = v["pc_requirements"].(models.PcRequirementModel) // 配置需求
-			case "US":
-				// 数据库部分
-				dbRecordEN.Language = v["supported_languages"].(string)               // 支持的语言
-				dbRecordEN.Developer = strings.Join(v["developers"].([]string), ", ") // 开发商
-				dbRecordEN.Publisher = strings.Join(v["publishers"].([]string), ", ") // 发行商
-				dbRecordEN.Cover = v["header_image"].(string)                         // 封面图
-				dbRecordEN.Info = v["short_description"].(string)                     // 概述
-				// 发行时间
-				if v["release_date"].(models.SteamAppRelease).ComingSoon {
-					dbRecordEN.ReleaseDate = "即将推出"
-				} else {
-					dbRecordEN.ReleaseDate = v["release_date"].(models.SteamAppRelease).Date
-				}
-				// 支持平台
-				platform := v["platforms"].(models.SteamAppPlatform)
-				var platforms []string
-				if platform.Windows {
-					platforms = append(platforms, "windows")
-				}
-				if platform.Mac {
-					platforms = append(platforms, "mac")
-				}
-				if platform.Linux {
-					platforms = append(platforms, "linux")
-				}
-				dbRecordEN.Platform = strings.Join(platforms, ", ")
-				// redis 部分
-				redisRecordEN.Support = v["support_info"].(models.SteamAppSupport)              // 开发商联系方式
-				redisRecordEN.Screenshots = v["screenshots"].([]models.SteamAppScreenshot)      // 游戏图片
-				redisRecordEN.Movies = v["movies"].([]models.SteamAppMovie)                     // 游戏视频
-				redisRecordEN.SupportedLanguages = dbRecordCN.Language                          // 支持语言
-				redisRecordEN.Developers = dbRecordCN.Developer                                 // 开发商
-				redisRecordEN.Publishers = dbRecordCN.Publisher                                 // 发行商
-				redisRecordEN.HeaderImage = dbRecordCN.Cover                                    // 封面图
-				redisRecordEN.ShortDescription = dbRecordCN.Info                                // 概述
-				redisRecordEN.Date = dbRecordCN.ReleaseDate                                     // 发行日期
-				redisRecordEN.Platforms = dbRecordCN.Platform                                   // 支持平台
-				redisRecordEN.RequiredAge = v["required_age"].(string)                          // 年龄限制
-				redisRecordEN.Website = v["website"].(string)                                   // 游戏官网
-				redisRecordEN.ContentDescriptors = v["content_descriptors"].(string)            // 内容描述
-				redisRecordEN.CollectDate = cm.LocalTime(time.Now())                            // 采集时间
-				redisRecordEN.DetailedDescription = v["detailed_description"].(string)          // 详情简介
-				redisRecordEN.AboutTheGame = v["about_the_game"].(string)                       // 关于游戏
-				redisRecordEN.PcRequirements = v["pc_requirements"].(models.PcRequirementModel) // 配置需求
-			}
-		}
-
-		// 存数据库
-		enRecord, err := dao.GetGameDao().GetGameRecordByGameIDAndLang(gameID.ID, "en")
-		if err != nil && err.GetMsg() == "record not found" {
-			dao.GetGameDao().Add(&dbRecordEN)
-		} else if err == nil {
-			dbRecordEN.ID = enRecord.ID
-			dao.GetGameDao().Update(enRecord.ID, &dbRecordEN)
-		}
-		zhRecord, err := dao.GetGameDao().GetGameRecordByGameIDAndLang(gameID.ID, "zh")
-		if err != nil && err.GetMsg() == "record not found" {
-			dao.GetGameDao().Add(&dbRecordCN)
-		} else if err == nil {
-			dbRecordCN.ID = zhRecord.ID
-			dao.GetGameDao().Update(zhRecord.ID, &dbRecordCN)
-		}
-
-		// 存 redis
-		idStr := util.Int642String(gameID.ID)
-		jsonResultCN, _ := sonic.Marshal(redisRecordCN)
-		cs.SetNX("game:zh-info"+idStr, string(jsonResultCN), 168*time.Hour)     // 创建记录
-		cs.SetExpire("game:zh-info"+idStr, string(jsonResultCN), 168*time.Hour) // 更新记录
-
-		jsonResultEN, _ := sonic.Marshal(redisRecordEN)
-		cs.SetNX("game:en-info"+idStr, string(jsonResultEN), 168*time.Hour)     // 创建记录
-		cs.SetExpire("game:en-info"+idStr, string(jsonResultEN), 168*time.Hour) // 更新记录
-	}
-}
-
-// performGameCollect 执行游戏记录采集
-func performGameCollect(gameID models.GameID) (map[string]models.SteamAppPrice, map[string]map[string]any) {
-	defer func() {
-		if err := recover(); err != nil {
-			log.Error("receive performGameCollect recover: ", err)
+		infoRes, gfErr := src.FetchDetails(gameID)
+		if gfErr != nil {
+			log.Warn(src.Name(), " FetchDetails 失败, game_id=", gameID.ID, " err:", gfErr.GetMsg())
+			run.fail(STAGE_DETAIL, src.Name(), gameID, gfErr.GetMsg())
+			return
 		}
-	}()
-
-	appidStr := util.Int642String(gameID.Appid)
-
-	var priceRes = make(map[string]models.SteamAppPrice)
-	var infoRes = make(map[string]map[string]any)
-
-	// 请求地址
-	url := `https://store.steampowered.com/api/appdetails`
-
-	// 设置参数
-	paramsMap := map[string]string{
-		"appids": appidStr,
-		"cc":     "",
-	}
-
-	// 采中文和英文两种版本
-	nowHeaders := headersMap
-	nowLang := "CN"
-	for _, lang := range langList {
-		// 设置采集的国区(价格)和语言
-		paramsMap["cc"] = lang
-		switch lang {
-		case "US":
-			nowHeaders["Accept-Language"] = common.ACCEPT_LANGUAGE_EN
-			nowLang = "US"
-		default:
-			nowHeaders["Accept-Language"] = common.ACCEPT_LANGUAGE_CN
-			nowLang = "CN"
-		}
-
-		if infoRes[nowLang] == nil {
-			infoRes[nowLang] = make(map[string]any)
-		}
-
-		// 请求 SteamAPI
-		respDataStr, httpErr := util.GetByHttpWithParams(url, headersMap, paramsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
-		if httpErr != nil {
-			log.Warn(httpErr)
-			return priceRes, infoRes
-		}
-
-		// 判断是否成功, 锁区游戏国区请求会返回错误
-		if !gjson.Get(respDataStr, appidStr+".success").Bool() {
-			continue
+		priceRes, gfErr := src.FetchPrices(gameID)
+		if gfErr != nil {
+			log.Warn(src.Name(), " FetchPrices 失败, game_id=", gameID.ID, " err:", gfErr.GetMsg())
+			run.fail(STAGE_DETAIL, src.Name(), gameID, gfErr.GetMsg())
+			return
 		}
+		run.succeed(STAGE_DETAIL, src.Name(), gameID)
 
 		// 是否免费
-		isFree := gjson.Get(respDataStr, appidStr+".data.is_free").String()
-		if isFree == "true" {
-			// 免费
-			priceRes["free"] = models.SteamAppPrice{
-				Currency: "免费",
-			}
-		} else {
-			// 不免费
-			priceDataStr := gjson.Get(respDataStr, appidStr+".data.price_overview").String()
-			if priceDataStr != "" {
-				var newPrice models.SteamAppPrice
-				jsonErr := sonic.Unmarshal([]byte(priceDataStr), &newPrice)
-				if jsonErr != nil {
-					log.Error("models.SteamAppPrice json转换错误.", jsonErr)
-				}
-				priceRes[lang] = newPrice
-			} else {
-				priceRes[lang] = models.SteamAppPrice{}
-			}
+		isFree := false
+		for _, v := range infoRes {
+			isFree = isFree || v.IsFree
 		}
 
-		// 支持的语言
-		if _, exist := infoRes[nowLang]["supported_languages"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.supported_languages").String()
-			infoRes[nowLang]["supported_languages"] = tempDataStr
-		}
+		// 处理价格列表
+		priceListStr := buildPriceList(priceRes, isFree)
 
-		// 发行日期
-		if _, exist := infoRes[nowLang]["release_date"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.release_date").String()
-			var newDate models.SteamAppRelease
-			if tempDataStr != "" {
-				jsonErr := sonic.Unmarshal([]byte(tempDataStr), &newDate)
-				if jsonErr != nil {
-					log.Error("models.SteamAppRelease json转换错误.", jsonErr)
-				}
-				infoRes[nowLang]["release_date"] = newDate
-			} else {
-				infoRes[nowLang]["release_date"] = models.SteamAppRelease{}
-			}
-		}
+		// 保存各国区价格
+		saveGamePrice(src, gameID, priceRes, isFree)
 
-		// 支持平台
-		if _, exist := infoRes[nowLang]["platforms"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.platforms").String()
-			var newPlatform models.SteamAppPlatform
-			if tempDataStr != "" {
-				jsonErr := sonic.Unmarshal([]byte(tempDataStr), &newPlatform)
-				if jsonErr != nil {
-					log.Error("models.SteamAppPlatform json转换错误.", jsonErr)
-				}
-				infoRes[nowLang]["platforms"] = newPlatform
-			} else {
-				infoRes[nowLang]["platforms"] = models.SteamAppPlatform{}
-			}
-		}
+		// 保存类型、功能和用户标签
+		saveGameTags(src, gameID, infoRes)
 
-		// 开发商
-		if _, exist := infoRes[nowLang]["developers"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.developers").String()
-			var newDevelopers []string
-			jsonErr := sonic.Unmarshal([]byte(tempDataStr), &newDevelopers)
-			if jsonErr != nil {
-				log.Error("newDevelopers json转换错误.", jsonErr)
-			}
-			infoRes[nowLang]["developers"] = newDevelopers
+		// 保存 DLC、原声音乐、试玩版和礼包, 各语言的结果相同, 取任意一种
+		for _, lang := range util.SortedKeys(infoRes) {
+			saveGameRelations(src, gameID, infoRes[lang])
+			saveGamePackages(src, gameID, infoRes[lang])
+			break
 		}
 
-		// 发行商
-		if _, exist := infoRes[nowLang]["publishers"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.publishers").String()
-			var newPublishers []string
-			jsonErr := sonic.Unmarshal([]byte(tempDataStr), &newPublishers)
-			if jsonErr != nil {
-				log.Error("newPublishers json转换错误.", jsonErr)
+		// 按语言保存记录
+		idStr := util.Int642String(gameID.ID)
+		keyPrefix := redisKeyPrefix(src, gameID)
+		releaseDateChanged := false
+		for _, lang := range util.SortedKeys(infoRes) {
+			dbRecord, redisRecord := buildGameRecord(gameID, lang, infoRes[lang])
+			dbRecord.Store, redisRecord.Store = src.Name(), src.Name()
+			dbRecord.PriceList, redisRecord.PriceList = priceListStr, priceListStr
+
+			// 替换为镜像地址
+			mirrorGameMedia(gameID, &dbRecord, &redisRecord)
+
+			// 处理价格结果
+			if price, exist := langPrice(lang, priceRes, isFree); exist {
+				dbRecord.Initial = price.Initial
+				dbRecord.Final = price.Final
+				dbRecord.Discount = price.DiscountPercent
+
+				redisRecord.Price = price
 			}
-			infoRes[nowLang]["publishers"] = newPublishers
-		}
-
-		// 封面图
-		if _, exist := infoRes[nowLang]["header_image"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.header_image").String()
-			infoRes[nowLang]["header_image"] = tempDataStr
-		}
-
-		// 概述
-		if _, exist := infoRes[nowLang]["short_description"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.short_description").String()
-			infoRes[nowLang]["short_description"] = tempDataStr
-		}
 
-		// 其他信息
-		// 年龄限制
-		if _, exist := infoRes[nowLang]["required_age"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.ratings.steam_germany.required_age").String()
-			infoRes[nowLang]["required_age"] = tempDataStr
-		}
-
-		// 开发商联系方式
-		if _, exist := infoRes[nowLang]["support_info"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.support_info").String()
-			var newSupport models.SteamAppSupport
-			if tempDataStr != "" {
-				jsonErr := sonic.Unmarshal([]byte(tempDataStr), &newSupport)
-				if jsonErr != nil {
-					log.Error("models.SteamAppSupport json转换错误.", jsonErr)
+			// 存数据库
+			record, err := dao.GetGameDao().GetGameRecordByGameIDAndLang(gameID.ID, lang, src.Name())
+			if err != nil && err.GetMsg() == "record not found" {
+				dao.GetGameDao().Add(&dbRecord)
+			} else if err == nil {
+				dbRecord.ID = record.ID
+				dao.GetGameDao().Update(record.ID, &dbRecord)
+				// Update 会跳过零值, 价格字段单独更新
+				if err = dao.GetGameDao().UpdateGameRecordPrice(gameID.ID, lang, src.Name(), dbRecord.PriceList, dbRecord.Initial, dbRecord.Final, dbRecord.Discount); err != nil {
+					log.Error("UpdateGameRecordPrice error: ", err.GetMsg())
 				}
-				infoRes[nowLang]["support_info"] = newSupport
-			} else {
-				infoRes[nowLang]["support_info"] = models.SteamAppSupport{}
-			}
-		}
-
-		// 游戏官网
-		if _, exist := infoRes[nowLang]["website"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.website").String()
-			infoRes[nowLang]["website"] = tempDataStr
-		}
-
-		// 内容描述
-		if _, exist := infoRes[nowLang]["content_descriptors"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.content_descriptors.notes").String()
-			infoRes[nowLang]["content_descriptors"] = tempDataStr
-		}
 
-		// 游戏图片
-		if _, exist := infoRes[nowLang]["screenshots"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.screenshots").String()
-			var newScreenshot []models.SteamAppScreenshot
-			if tempDataStr != "" {
-				jsonErr := sonic.Unmarshal([]byte(tempDataStr), &newScreenshot)
-				if jsonErr != nil {
-					log.Error("models.SteamAppScreenshot json转换错误.", jsonErr)
+				// 发行日期变化, 每个游戏只通知一次
+				if !releaseDateChanged && record.ReleaseDate != "" && dbRecord.ReleaseDate != "" && record.ReleaseDate != dbRecord.ReleaseDate {
+					releaseDateChanged = true
+					emitEvent(models.GfgGameEvent{
+						GameID:    gameID.ID,
+						Store:     src.Name(),
+						EventType: models.EVENT_RELEASE_DATE_CHANGED,
+						Detail:    record.ReleaseDate + " -> " + dbRecord.ReleaseDate,
+						URL:       infoRes[lang].Website,
+					})
 				}
-				infoRes[nowLang]["screenshots"] = newScreenshot
-			} else {
-				infoRes[nowLang]["screenshots"] = []models.SteamAppScreenshot{}
 			}
-		}
 
-		// 游戏视频
-		if _, exist := infoRes[nowLang]["movies"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.movies").String()
-			var newMovie []models.SteamAppMovie
-			if tempDataStr != "" {
-				jsonErr := sonic.Unmarshal([]byte(tempDataStr), &newMovie)
-				if jsonErr != nil {
-					log.Error("models.SteamAppMovie json转换错误.", jsonErr)
-				}
-				infoRes[nowLang]["movies"] = newMovie
-			} else {
-				infoRes[nowLang]["movies"] = []models.SteamAppMovie{}
+			// 存 mongodb 游戏简介, 只保存主数据源
+			if isPrimarySource(src, gameID) {
+				saveGameIntro(gameID, lang, redisRecord)
 			}
-		}
-
-		// 详情描述
-		if _, exist := infoRes[nowLang]["detailed_description"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.detailed_description").String()
-			infoRes[nowLang]["detailed_description"] = tempDataStr
-		}
-
-		// 关于游戏
-		if _, exist := infoRes[nowLang]["about_the_game"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.about_the_game").String()
-			infoRes[nowLang]["about_the_game"] = tempDataStr
-		}
 
-		// 配置需求
-		if _, exist := infoRes[nowLang]["pc_requirements"]; !exist {
-			tempDataStr := gjson.Get(respDataStr, appidStr+".data.pc_requirements").String()
-			var newRequirement models.PcRequirementModel
-			if tempDataStr != "" {
-				jsonErr := sonic.Unmarshal([]byte(tempDataStr), &newRequirement)
-				if jsonErr != nil {
-					log.Error("models.PcRequirementModel json转换错误.", jsonErr)
-				}
-				infoRes[nowLang]["pc_requirements"] = newRequirement
-			} else {
-				infoRes[nowLang]["pc_requirements"] = models.PcRequirementModel{}
-			}
+			// 存 redis
+			jsonResult, _ := sonic.Marshal(redisRecord)
+			cs.SetNX(keyPrefix+lang+"-info"+idStr, string(jsonResult), 168*time.Hour)     // 创建记录
+			cs.SetExpire(keyPrefix+lang+"-info"+idStr, string(jsonResult), 168*time.Hour) // 更新记录
 		}
 	}
+}
 
-	return priceRes, infoRes
+// 将商店详情转换为数据库记录和 redis 记录
+func buildGameRecord(gameID models.GameID, lang string, v models.StoreDetail) (dbRecord models.GfgGameRecord, redisRecord models.GameSaveModel) {
+	// 数据库部分
+	dbRecord.ID = util.GenerateId()
+	dbRecord.GameID = gameID.ID
+	dbRecord.Lang = lang
+	dbRecord.Language = v.SupportedLanguages              // 支持的语言
+	dbRecord.Developer = strings.Join(v.Developers, ", ") // 开发商
+	dbRecord.Publisher = strings.Join(v.Publishers, ", ") // 发行商
+	dbRecord.Cover = v.HeaderImage                        // 封面图
+	dbRecord.Info = v.ShortDescription                    // 概述
+	// 发行时间
+	if v.ReleaseDate.ComingSoon {
+		dbRecord.ReleaseDate = "即将推出"
+	} else {
+		dbRecord.ReleaseDate = v.ReleaseDate.Date
+	}
+	// 支持平台
+	var platforms []string
+	if v.Platforms.Windows {
+		platforms = append(platforms, "windows")
+	}
+	if v.Platforms.Mac {
+		platforms = append(platforms, "mac")
+	}
+	if v.Platforms.Linux {
+		platforms = append(platforms, "linux")
+	}
+	dbRecord.Platform = strings.Join(platforms, ", ")
+
+	// redis 部分
+	redisRecord.Name = v.Name                             // 游戏名称
+	redisRecord.Support = v.SupportInfo                   // 开发商联系方式
+	redisRecord.Screenshots = v.Screenshots               // 游戏图片
+	redisRecord.Movies = v.Movies                         // 游戏视频
+	redisRecord.SupportedLanguages = dbRecord.Language    // 支持语言
+	redisRecord.Developers = dbRecord.Developer           // 开发商
+	redisRecord.Publishers = dbRecord.Publisher           // 发行商
+	redisRecord.HeaderImage = dbRecord.Cover              // 封面图
+	redisRecord.ShortDescription = dbRecord.Info          // 概述
+	redisRecord.Date = dbRecord.ReleaseDate               // 发行日期
+	redisRecord.Platforms = dbRecord.Platform             // 支持平台
+	redisRecord.RequiredAge = v.RequiredAge               // 年龄限制
+	redisRecord.Website = v.Website                       // 游戏官网
+	redisRecord.ContentDescriptors = v.ContentDescriptors // 内容描述
+	redisRecord.CollectDate = cm.LocalTime(time.Now())    // 采集时间
+	redisRecord.Genres = tagNames(v.Genres)               // 类型
+	redisRecord.Categories = tagNames(v.Categories)       // 功能
+	redisRecord.Tags = tagNames(v.Tags)                   // 用户标签
+	redisRecord.HasDemo = len(v.Demos) > 0                // 是否有试玩版
+	// 富文本过滤后保存, 同时保留原始内容
+	redisRecord.DetailedDescription = sanitizeHTML(v.DetailedDescription)               // 详情简介
+	redisRecord.AboutTheGame = sanitizeHTML(v.AboutTheGame)                             // 关于游戏
+	redisRecord.PcRequirements.Minimum = sanitizeHTML(v.PcRequirements.Minimum)         // 最低配置
+	redisRecord.PcRequirements.Recommended = sanitizeHTML(v.PcRequirements.Recommended) // 推荐配置
+	redisRecord.DetailedDescriptionRaw = v.DetailedDescription
+	redisRecord.AboutTheGameRaw = v.AboutTheGame
+	redisRecord.PcRequirementsRaw = v.PcRequirements
+	return
 }
 
 // 开始游戏更新公告采集
-func startGameNewsCollect(gameID models.GameID) func() {
+func startGameNewsCollect(run *jobRun, src Source, gameID models.GameID) func() {
 	return func() {
+		defer run.done() // 确保线程结束时组数减少
 		defer func() {
 			if err := recover(); err != nil {
 				log.Error("receive startGameNewsCollect recover: ", err)
+				run.fail(STAGE_NEWS, src.Name(), gameID, fmt.Sprint(err))
 			}
 		}()
-		defer wg.Done() // 确保线程结束时组数减少
-
-		// 执行采集获取结果
-		cnt, cntStr := 10, "10" //采集 cnt 篇更新公告
-		newsResEN, newsResCN := performGameNewsCollect(gameID, cnt, cntStr)
-
-		for i := 0; i < cnt; i++ {
-			idx := util.Int2String(i)
-
-			// steam 更新公告接口若不足 num 篇公告就返回空
-			if newsResEN[idx].Title == "" && newsResEN[idx].Contents == "" &&
-				newsResCN[idx].Title == "" && newsResCN[idx].Contents == "" {
-				continue
-			}
-
-			saveModelEN := models.GfgGameNews{
-				ID:       util.GenerateId(),
-				GameID:   gameID.ID,
-				Headline: newsResEN[idx].Title,
-				Content:  newsResEN[idx].Contents,
-				Index:    int64(i),
-				PostTime: newsResEN[idx].Date,
-				Author:   newsResEN[idx].Author,
-				URL:      newsResEN[idx].URL,
-				Total:    newsResEN[idx].Count,
-				Lang:     "en",
-			}
-
-			saveModelCN := models.GfgGameNews{
-				ID:       util.GenerateId(),
-				GameID:   gameID.ID,
-				Headline: newsResCN[idx].Title,
-				Content:  newsResCN[idx].Contents,
-				Index:    int64(i),
-				PostTime: newsResCN[idx].Date,
-				Author:   newsResCN[idx].Author,
-				URL:      newsResCN[idx].URL,
-				Total:    newsResCN[idx].Count,
-				Lang:     "zh",
-			}
-
-			// 储存到数据库
-			zhRecord, err := dao.GetGameNewsDao().GetGameNews(gameID.ID, "zh", int64(i))
-			if err != nil && err.GetMsg() == "record not found" {
-				saveModelCN.CreateTime = cm.LocalTime(time.Now())
-				dao.GetGameNewsDao().Add(&saveModelCN)
-			} else if err == nil {
-				saveModelCN.CreateTime = zhRecord.CreateTime
-				saveModelCN.ID = zhRecord.ID
-				dao.GetGameNewsDao().Update(zhRecord.ID, &saveModelCN)
-			}
-
-			enRecord, err := dao.GetGameNewsDao().GetGameNews(gameID.ID, "en", int64(i))
-			if err != nil && err.GetMsg() == "record not found" {
-				saveModelEN.CreateTime = cm.LocalTime(time.Now())
-				dao.GetGameNewsDao().Add(&saveModelEN)
-			} else if err == nil {
-				saveModelEN.CreateTime = enRecord.CreateTime
-				saveModelEN.ID = enRecord.ID
-				dao.GetGameNewsDao().Update(enRecord.ID, &saveModelEN)
-			}
-
-			// 储存到 redis
-			idStr := util.Int642String(gameID.ID)
-			jsonResultCN, _ := sonic.Marshal(saveModelCN)
-			cs.SetNX("game:zh-news"+idStr+"-"+idx, string(jsonResultCN), 168*time.Hour)     // 创建记录
-			cs.SetExpire("game:zh-news"+idStr+"-"+idx, string(jsonResultCN), 168*time.Hour) // 更新记录
-
-			jsonResultEN, _ := sonic.Marshal(saveModelCN)
-			cs.SetNX("game:en-news"+idStr+"-"+idx, string(jsonResultEN), 168*time.Hour)     // 创建记录
-			cs.SetExpire("game:en-news"+idStr+"-"+idx, string(jsonResultEN), 168*time.Hour) // 更新记录
 
+		// 增量采集并刷新最新公告列表
+		if gfErr := collectGameNews(src, gameID); gfErr != nil && !isNotSupported(gfErr) {
+			log.Warn(src.Name(), " 公告采集失败, game_id=", gameID.ID, " err:", gfErr.GetMsg())
+			run.fail(STAGE_NEWS, src.Name(), gameID, gfErr.GetMsg())
+			return
 		}
-
-	}
-}
-
-// 执行游戏更新公告采集
-func performGameNewsCollect(gameID models.GameID, cnt int, cntStr string) (map[string]models.SteamAppNews, map[string]models.SteamAppNews) {
-	defer func() {
-		if err := recover(); err != nil {
-			log.Error("receive performGameNewsCollect recover: ", err)
-		}
-	}()
-
-	appidStr := util.Int642String(gameID.Appid)
-
-	// 请求地址
-	apiUrl := `https://api.steampowered.com/ISteamNews/GetNewsForApp/v2`             // steamAPI 仅返回英文 请求速度慢
-	storeUrl := `https://store.steampowered.com/events/ajaxgetadjacentpartnerevents` // 商店API 返回语言可选 请求速度快
-
-	// 设置参数
-	apiParamsMap := map[string]string{
-		"appid": appidStr,
-		"count": cntStr,
-	}
-	storeParamsMap := map[string]string{
-		"appid":        appidStr,
-		"count_before": "1",
-		"count_after":  cntStr,
-		"lang_list":    "6_0",
-	}
-
-	var newsResEN = make(map[string]models.SteamAppNews)
-	var newsResCN = make(map[string]models.SteamAppNews)
-
-	// SteamAPI 请求英文数据
-	// 请求 SteamAPI
-	respDataStr, httpErr := util.GetByHttpWithParams(apiUrl, headersMap, apiParamsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
-	if httpErr != nil {
-		log.Warn("api.steampowered.com/ISteamNews/GetNewsForApp 请求失败", httpErr)
-		return newsResEN, newsResCN
-	}
-
-	// 解析 cnt 篇更新公告
-	for i := 0; i < cnt; i++ {
-		idx := util.Int2String(i)
-
-		// 存储结构
-		nowNews := models.SteamAppNews{}
-
-		nowNews.Title = gjson.Get(respDataStr, "appnews.newsitems."+idx+".title").String()   // 标题
-		nowNews.Author = gjson.Get(respDataStr, "appnews.newsitems."+idx+".author").String() // 作者
-		nowNews.URL = gjson.Get(respDataStr, "appnews.newsitems."+idx+".url").String()       // URL
-		nowNews.Count = gjson.Get(respDataStr, "appnews.count").Int()                        // 更新公告数
-		// 日期
-		date := gjson.Get(respDataStr, "appnews.newsitems."+idx+".date").Int()
-		loc, _ := time.LoadLocation("Asia/Shanghai") // 中国 CST（UTC+8）
-		cstTime := time.Unix(date, 0).In(loc)
-		nowNews.Date = cm.LocalTime(cstTime)
-		// 内容
-		content := gjson.Get(respDataStr, "appnews.newsitems."+idx+".contents").String()
-		nowNews.Contents = util.ParseBBCode(content)
-
-		// 存储结果
-		newsResEN[idx] = nowNews
-	}
-
-	// SteamStoreAPI 请求中文数据
-	respDataStr, httpErr = util.GetByHttpWithParams(storeUrl, headersMap, storeParamsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
-	if httpErr != nil {
-		log.Warn("store.steampowered.com/events/ajaxgetadjacentpartnerevents 请求失败", httpErr)
-		return newsResEN, newsResCN
-	}
-
-	// 解析 cnt 篇更新公告
-	for i := 0; i < cnt; i++ {
-		idx := util.Int2String(i)
-
-		// 存储结构
-		nowNews := models.SteamAppNews{}
-
-		nowNews.Author = newsResEN[idx].Author                                                       // 作者
-		nowNews.URL = newsResEN[idx].URL                                                             // URL
-		nowNews.Date = newsResEN[idx].Date                                                           // 日期
-		nowNews.Count = newsResEN[idx].Count                                                         // 更新公告数
-		nowNews.Title = gjson.Get(respDataStr, "events."+idx+".announcement_body.headline").String() // 标题
-		// 内容
-		content := gjson.Get(respDataStr, "events."+idx+".announcement_body.body").String()
-		nowNews.Contents = util.ParseBBCode(content)
-
-		// 存储结果
-		newsResCN[idx] = nowNews
+		run.succeed(STAGE_NEWS, src.Name(), gameID)
 	}
-	return newsResEN, newsResCN
 }
 
 // 添加游戏记录到采集列表
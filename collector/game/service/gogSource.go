@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/GoFurry/gofurry-game-collector/roof/env"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tidwall/gjson"
+)
+
+// GOG 数据源的商店标识
+const STORE_GOG = "gog"
+
+// gogSource GOG 商店适配器
+type gogSource struct{}
+
+// GOG 接口的 locale, 简体中文为 zh-Hans, 其余为 语言-国区, 如 en-US
+func gogLocale(language env.LanguageConfig) string {
+	if language.Lang == "zh" {
+		return "zh-Hans"
+	}
+	return language.Lang + "-" + language.CC
+}
+
+func (s gogSource) Name() string { return STORE_GOG }
+
+func (s gogSource) Supports(gameID models.GameID) bool { return gameID.GogID != 0 }
+
+// 请求 GOG 接口
+func (s gogSource) get(apiUrl string, params map[string]string) (string, common.GFError) {
+	if err := gogLimiter.Wait(context.Background()); err != nil {
+		return "", common.NewServiceError("获取限流令牌失败: " + err.Error())
+	}
+	respDataStr, httpErr := util.GetByHttpWithParams(apiUrl, newHeaders(common.ACCEPT_LANGUAGE_EN), params, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+	if httpErr != nil {
+		return "", common.NewServiceError(httpErr.Error())
+	}
+	if !gjson.Valid(respDataStr) {
+		return "", common.NewServiceError("api.gog.com 返回格式错误: " + apiUrl)
+	}
+	return respDataStr, nil
+}
+
+// 补齐 GOG 返回的 // 开头的地址
+func gogURL(u string) string {
+	if strings.HasPrefix(u, "//") {
+		return "https:" + u
+	}
+	return u
+}
+
+// FetchDetails 按采集语言获取商品详情
+func (s gogSource) FetchDetails(gameID models.GameID) (map[string]models.StoreDetail, common.GFError) {
+	idStr := util.Int642String(gameID.GogID)
+	infoRes := make(map[string]models.StoreDetail)
+
+	// 开发商和发行商只在 v2 接口中提供, 与语言无关
+	v2DataStr, gfErr := s.get("https://api.gog.com/v2/games/"+idStr, nil)
+	if gfErr != nil {
+		return infoRes, gfErr
+	}
+	var developers, publishers []string
+	for _, v := range gjson.Get(v2DataStr, "_embedded.developers.#.name").Array() {
+		developers = append(developers, v.String())
+	}
+	if publisher := gjson.Get(v2DataStr, "_embedded.publisher.name").String(); publisher != "" {
+		publishers = append(publishers, publisher)
+	}
+
+	for _, language := range getLanguageList() {
+		paramsMap := map[string]string{
+			"expand": "description,screenshots,videos",
+			"locale": gogLocale(language),
+		}
+		dataStr, gfErr := s.get("https://api.gog.com/products/"+idStr, paramsMap)
+		if gfErr != nil {
+			return infoRes, gfErr
+		}
+		if gjson.Get(dataStr, "id").Int() == 0 {
+			continue
+		}
+
+		var detail models.StoreDetail
+		detail.Name = gjson.Get(dataStr, "title").String()                                   // 游戏名称
+		detail.HeaderImage = gogURL(gjson.Get(dataStr, "images.logo2x").String())            // 封面图
+		detail.ShortDescription = gjson.Get(dataStr, "description.lead").String()            // 概述
+		detail.DetailedDescription = gjson.Get(dataStr, "description.full").String()         // 详情描述
+		detail.AboutTheGame = gjson.Get(dataStr, "description.whats_cool_about_it").String() // 关于游戏
+		detail.Website = gjson.Get(dataStr, "links.product_card").String()                   // 商品页
+		detail.SupportInfo.URL = gjson.Get(dataStr, "links.support").String()                // 支持页
+		detail.Developers, detail.Publishers = developers, publishers
+
+		// 发行日期
+		detail.ReleaseDate.ComingSoon = gjson.Get(dataStr, "is_pre_order").Bool() || gjson.Get(dataStr, "in_development.active").Bool()
+		if releaseDate := gjson.Get(dataStr, "release_date").String(); releaseDate != "" {
+			detail.ReleaseDate.Date, _, _ = strings.Cut(releaseDate, "T")
+		}
+
+		// 支持平台
+		detail.Platforms.Windows = gjson.Get(dataStr, "content_system_compatibility.windows").Bool()
+		detail.Platforms.Mac = gjson.Get(dataStr, "content_system_compatibility.osx").Bool()
+		detail.Platforms.Linux = gjson.Get(dataStr, "content_system_compatibility.linux").Bool()
+
+		// 支持的语言
+		var languages []string
+		gjson.Get(dataStr, "languages").ForEach(func(key, value gjson.Result) bool {
+			languages = append(languages, value.String())
+			return true
+		})
+		detail.SupportedLanguages = strings.Join(languages, ", ")
+
+		// 游戏图片
+		detail.Screenshots = []models.SteamAppScreenshot{}
+		for i, v := range gjson.Get(dataStr, "screenshots").Array() {
+			template := v.Get("formatter_template_url").String()
+			detail.Screenshots = append(detail.Screenshots, models.SteamAppScreenshot{
+				ID:            int64(i),
+				PathThumbnail: strings.Replace(template, "{formatter}", "ggvgm", 1),
+				PathFull:      strings.Replace(template, "_{formatter}", "", 1),
+			})
+		}
+
+		// 游戏视频
+		detail.Movies = []models.SteamAppMovie{}
+		for i, v := range gjson.Get(dataStr, "videos").Array() {
+			detail.Movies = append(detail.Movies, models.SteamAppMovie{
+				ID:        int64(i),
+				Name:      v.Get("provider").String(),
+				Thumbnail: v.Get("thumbnail_url").String(),
+				Embed:     v.Get("video_url").String(),
+			})
+		}
+
+		infoRes[language.Lang] = detail
+	}
+
+	return infoRes, nil
+}
+
+// FetchPrices 按采集国区获取价格
+func (s gogSource) FetchPrices(gameID models.GameID) (map[string]models.SteamAppPrice, common.GFError) {
+	idStr := util.Int642String(gameID.GogID)
+	priceRes := make(map[string]models.SteamAppPrice)
+
+	for _, region := range getRegionList() {
+		dataStr, gfErr := s.get("https://api.gog.com/products/"+idStr+"/prices", map[string]string{"countryCode": region.CC})
+		if gfErr != nil {
+			return priceRes, gfErr
+		}
+
+		// 未在该国区销售时没有价格
+		price := gjson.Get(dataStr, "_embedded.prices.0")
+		if !price.Exists() {
+			continue
+		}
+		initial, currency := parseGogPrice(price.Get("basePrice").String())
+		final, _ := parseGogPrice(price.Get("finalPrice").String())
+
+		var discount int64
+		if initial > 0 && final < initial {
+			discount = (initial - final) * 100 / initial
+		}
+		priceRes[region.CC] = models.SteamAppPrice{
+			Initial:          initial,
+			Final:            final,
+			Currency:         currency,
+			DiscountPercent:  discount,
+			InitialFormatted: formatGogPrice(initial, currency),
+			FinalFormatted:   formatGogPrice(final, currency),
+		}
+	}
+
+	return priceRes, nil
+}
+
+// 将 1999 USD 形式的价格拆分为以分为单位的价格和货币代码
+func parseGogPrice(priceStr string) (int64, string) {
+	amount, currency, _ := strings.Cut(strings.TrimSpace(priceStr), " ")
+	price, _ := strconv.ParseInt(amount, 10, 64)
+	return price, currency
+}
+
+// 价格展示格式
+func formatGogPrice(price int64, currency string) string {
+	return fmt.Sprintf("%d.%02d %s", price/100, price%100, currency)
+}
+
+// FetchNews GOG 没有公告, 将商品更新日志按版本拆分为公告
+func (s gogSource) FetchNews(gameID models.GameID, cnt int) (map[string][]models.SteamAppNews, common.GFError) {
+	idStr := util.Int642String(gameID.GogID)
+	newsRes := make(map[string][]models.SteamAppNews)
+
+	for _, language := range getLanguageList() {
+		paramsMap := map[string]string{
+			"expand": "changelog",
+			"locale": gogLocale(language),
+		}
+		dataStr, gfErr := s.get("https://api.gog.com/products/"+idStr, paramsMap)
+		if gfErr != nil {
+			return newsRes, gfErr
+		}
+
+		changelog := gjson.Get(dataStr, "changelog").String()
+		if changelog == "" {
+			continue
+		}
+		title := gjson.Get(dataStr, "title").String()
+		productURL := gjson.Get(dataStr, "links.product_card").String()
+
+		// 更新日志没有id和发布时间, 以版本标题区分不同版本
+		// 标题中没有日期时 Date 为空, 保存时记录首次采集时间
+		entries := splitGogChangelog(changelog)
+		if len(entries) == 0 {
+			// 没有版本标题时整篇作为一篇公告
+			entries = []gogChangelogEntry{{Content: changelog}}
+		}
+		gidSet := make(map[string]bool)
+		for i, entry := range entries {
+			if i >= cnt {
+				break
+			}
+			headline := title + " Changelog"
+			gid := util.MD5(entry.Content)
+			if entry.Title != "" {
+				headline = title + " " + entry.Title
+				gid = util.MD5(entry.Title)
+			}
+			// 版本标题重复时再加上内容区分
+			if gidSet[gid] {
+				gid = util.MD5(entry.Title + entry.Content)
+			}
+			gidSet[gid] = true
+
+			newsRes[language.Lang] = append(newsRes[language.Lang], models.SteamAppNews{
+				Gid:      gid,
+				Title:    headline,
+				Author:   "GOG",
+				URL:      productURL,
+				Contents: entry.Content,
+				Date:     cm.LocalTime(entry.Date),
+				Count:    int64(len(entries)),
+			})
+		}
+	}
+	return newsRes, nil
+}
+
+// 更新日志中的一个版本
+type gogChangelogEntry struct {
+	Title   string    // 版本标题
+	Content string    // 版本内容
+	Date    time.Time // 标题中的日期, 没有时为零值
+}
+
+// 标题在正文中的占位符, 使用 Unicode 私用区字符, 不会出现在更新日志中
+const gogHeadingMark = "\ue000"
+
+// 按 <h1>~<h6> 标题将更新日志拆分为版本, 标题可以嵌套在 <div> 等标签中, 保持原顺序, 没有标题时返回空
+func splitGogChangelog(changelog string) []gogChangelogEntry {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(changelog))
+	if err != nil {
+		return nil
+	}
+
+	// 记录标题后替换为占位符, 按占位符切分正文
+	var entries []gogChangelogEntry
+	doc.Find("h1,h2,h3,h4,h5,h6").Each(func(i int, sel *goquery.Selection) {
+		heading := strings.Join(strings.Fields(sel.Text()), " ")
+		entries = append(entries, gogChangelogEntry{
+			Title: strings.TrimSuffix(heading, ":"),
+			Date:  parseChangelogDate(heading),
+		})
+		sel.ReplaceWithHtml(gogHeadingMark)
+	})
+	if len(entries) == 0 {
+		return nil
+	}
+
+	body, err := doc.Find("body").Html()
+	if err != nil {
+		return nil
+	}
+	// 第一个标题之前的内容没有版本, 不保留
+	parts := strings.Split(body, gogHeadingMark)[1:]
+	for i := range entries {
+		entries[i].Content = closeGogFragment(parts[i])
+	}
+	return entries
+}
+
+// 切分后的片段可能有未闭合或多余的结束标签, 重新解析补齐, 并去掉切分留下的空标签
+func closeGogFragment(fragment string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(fragment))
+	if err != nil {
+		return strings.TrimSpace(fragment)
+	}
+	for {
+		empty := doc.Find("body *:empty").Not("br,hr,img,iframe,video,source")
+		if empty.Length() == 0 {
+			break
+		}
+		empty.Remove()
+	}
+	html, _ := doc.Find("body").Html()
+	return strings.TrimSpace(html)
+}
+
+// 更新日志标题中常见的日期格式
+var changelogDateFormats = []struct {
+	pattern *regexp.Regexp
+	layouts []string
+}{
+	{regexp.MustCompile(`\d{4}-\d{1,2}-\d{1,2}`), []string{"2006-1-2"}},
+	{regexp.MustCompile(`\d{4}/\d{1,2}/\d{1,2}`), []string{"2006/1/2"}},
+	{regexp.MustCompile(`\d{1,2}\.\d{1,2}\.\d{4}`), []string{"2.1.2006"}},
+	{regexp.MustCompile(`(?i)\d{1,2} [a-z]{3,9},? \d{4}`), []string{"2 January 2006", "2 Jan 2006", "2 January, 2006", "2 Jan, 2006"}},
+	{regexp.MustCompile(`(?i)[a-z]{3,9} \d{1,2}(st|nd|rd|th)?,? \d{4}`), []string{"January 2, 2006", "Jan 2, 2006", "January 2 2006", "Jan 2 2006"}},
+}
+
+var ordinalSuffix = regexp.MustCompile(`(?i)(\d)(st|nd|rd|th)`)
+
+// 解析标题中的日期, 没有时返回零值
+func parseChangelogDate(heading string) time.Time {
+	loc, _ := time.LoadLocation("Asia/Shanghai") // 中国 CST（UTC+8）
+	for _, v := range changelogDateFormats {
+		match := v.pattern.FindString(heading)
+		if match == "" {
+			continue
+		}
+		match = ordinalSuffix.ReplaceAllString(match, "$1")
+		for _, layout := range v.layouts {
+			if date, err := time.ParseInLocation(layout, match, loc); err == nil {
+				return date
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// FetchPlayerCount GOG 没有在线人数
+func (s gogSource) FetchPlayerCount(gameID models.GameID) (int64, common.GFError) {
+	return 0, common.NewServiceError(common.RETURN_NOT_SUPPORTED)
+}
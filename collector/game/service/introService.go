@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+)
+
+// 视频播放地址, 依次取 HLS、DASH 和外部嵌入地址
+func movieURL(movie models.SteamAppMovie) string {
+	for _, v := range []string{movie.HlsH264, movie.DashH264, movie.DashAv1, movie.Embed} {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// 保存游戏简介到 mongodb, 内容优先取关于游戏
+func saveGameIntro(gameID models.GameID, lang string, record models.GameSaveModel) {
+	intro := models.GameIntro{
+		GameID:  gameID.ID,
+		Lang:    lang,
+		Content: record.AboutTheGame,
+	}
+	if intro.Content == "" {
+		intro.Content = record.DetailedDescription
+	}
+	if intro.Content == "" {
+		return
+	}
+	for _, v := range record.Screenshots {
+		if v.PathFull != "" {
+			intro.Screenshots = append(intro.Screenshots, v.PathFull)
+			if v.Image != nil {
+				intro.ImageMetas = append(intro.ImageMetas, *v.Image)
+			} else {
+				intro.ImageMetas = append(intro.ImageMetas, models.ImageMeta{})
+			}
+		}
+	}
+	intro.HeaderImage = record.HeaderImageMeta
+	for _, v := range record.Movies {
+		if u := movieURL(v); u != "" {
+			intro.Videos = append(intro.Videos, u)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := dao.NewGameIntroDao().SaveOrUpdate(ctx, &intro); err != nil {
+		log.Error("SaveOrUpdate GameIntro error: ", err.GetMsg())
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/itch"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/roof/env"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tidwall/gjson"
+)
+
+// itch.io 数据源的商店标识
+const STORE_ITCH = "itch"
+
+// itchSource itch.io 商店适配器, 请求和解析见 itch 包
+type itchSource struct{}
+
+// 已解析的 itch.io 游戏地址, key 为 gfg_game.itch_url
+var itchURLCache sync.Map
+
+func (s itchSource) Name() string { return STORE_ITCH }
+
+func (s itchSource) Supports(gameID models.GameID) bool { return gameID.ItchURL != "" }
+
+// 解析 itch.io 游戏地址, 支持完整地址、creator/game 以及数字 id
+func (s itchSource) gameURL(key string) (string, common.GFError) {
+	key = strings.TrimSpace(key)
+	if v, ok := itchURLCache.Load(key); ok {
+		return v.(string), nil
+	}
+
+	var gameURL string
+	if strings.HasPrefix(key, "http://") || strings.HasPrefix(key, "https://") {
+		gameURL = strings.TrimRight(key, "/")
+	} else if _, err := strconv.ParseInt(key, 10, 64); err == nil {
+		// 数字 id 通过嵌入页找到游戏地址
+		if err := itchLimiter.Wait(context.Background()); err != nil {
+			return "", common.NewServiceError("获取限流令牌失败: " + err.Error())
+		}
+		var httpErr error
+		if gameURL, httpErr = itch.ResolveEmbed("https://itch.io/embed/"+key, &env.GetServerConfig().Collector.Proxy); httpErr != nil {
+			return "", common.NewServiceError(httpErr.Error())
+		}
+	} else if creator, game, ok := strings.Cut(key, "/"); ok {
+		gameURL = "https://" + creator + ".itch.io/" + game
+	}
+
+	if gameURL == "" {
+		return "", common.NewServiceError("无法解析 itch.io 游戏地址: " + key)
+	}
+	itchURLCache.Store(key, gameURL)
+	return gameURL, nil
+}
+
+// 请求 itch.io 游戏的 data.json
+func (s itchSource) fetchData(gameURL string) (string, common.GFError) {
+	if err := itchLimiter.Wait(context.Background()); err != nil {
+		return "", common.NewServiceError("获取限流令牌失败: " + err.Error())
+	}
+	dataStr, err := itch.FetchData(gameURL, &env.GetServerConfig().Collector.Proxy)
+	if err != nil {
+		return "", common.NewServiceError(err.Error())
+	}
+	return dataStr, nil
+}
+
+// 请求 itch.io 游戏页面
+func (s itchSource) fetchPage(gameURL string) (*goquery.Document, common.GFError) {
+	if err := itchLimiter.Wait(context.Background()); err != nil {
+		return nil, common.NewServiceError("获取限流令牌失败: " + err.Error())
+	}
+	doc, err := itch.FetchPage(gameURL, &env.GetServerConfig().Collector.Proxy)
+	if err != nil {
+		return nil, common.NewServiceError(err.Error())
+	}
+	return doc, nil
+}
+
+// FetchDetails 采集游戏页面, itch.io 不区分语言, 统一记录为英文
+func (s itchSource) FetchDetails(gameID models.GameID) (map[string]models.StoreDetail, common.GFError) {
+	gameURL, gfErr := s.gameURL(gameID.ItchURL)
+	if gfErr != nil {
+		return nil, gfErr
+	}
+	dataStr, gfErr := s.fetchData(gameURL)
+	if gfErr != nil {
+		return nil, gfErr
+	}
+	doc, gfErr := s.fetchPage(gameURL)
+	if gfErr != nil {
+		return nil, gfErr
+	}
+	return map[string]models.StoreDetail{"en": itch.ParseDetail(gameURL, dataStr, doc)}, nil
+}
+
+// FetchPrices 采集价格, itch.io 不区分国区, 统一按 US 记录
+// 货币代码取游戏页面中的商品信息, 因此同时请求 data.json 和游戏页面
+func (s itchSource) FetchPrices(gameID models.GameID) (map[string]models.SteamAppPrice, common.GFError) {
+	gameURL, gfErr := s.gameURL(gameID.ItchURL)
+	if gfErr != nil {
+		return nil, gfErr
+	}
+	dataStr, gfErr := s.fetchData(gameURL)
+	if gfErr != nil {
+		return nil, gfErr
+	}
+
+	priceRes := make(map[string]models.SteamAppPrice)
+	if gjson.Get(dataStr, "price").String() == "" {
+		return priceRes, nil
+	}
+	doc, gfErr := s.fetchPage(gameURL)
+	if gfErr != nil {
+		return nil, gfErr
+	}
+	if price, ok := itch.ParsePrice(dataStr, doc); ok {
+		priceRes["US"] = price
+	}
+	return priceRes, nil
+}
+
+// FetchNews 采集开发日志
+func (s itchSource) FetchNews(gameID models.GameID, cnt int) (map[string][]models.SteamAppNews, common.GFError) {
+	gameURL, gfErr := s.gameURL(gameID.ItchURL)
+	if gfErr != nil {
+		return nil, gfErr
+	}
+
+	if err := itchLimiter.Wait(context.Background()); err != nil {
+		return nil, common.NewServiceError("获取限流令牌失败: " + err.Error())
+	}
+	newsList, err := itch.FetchDevlog(gameURL, cnt, &env.GetServerConfig().Collector.Proxy)
+	if err != nil {
+		return nil, common.NewServiceError(err.Error())
+	}
+	newsRes := make(map[string][]models.SteamAppNews)
+	if len(newsList) > 0 {
+		newsRes["en"] = newsList
+	}
+	return newsRes, nil
+}
+
+// FetchPlayerCount itch.io 没有在线人数
+func (s itchSource) FetchPlayerCount(gameID models.GameID) (int64, common.GFError) {
+	return 0, common.NewServiceError(common.RETURN_NOT_SUPPORTED)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+)
+
+// 任务名称
+const (
+	JOB_COLLECT   = "collect"   // 游戏详情、公告和评测采集
+	JOB_PLAYERS   = "players"   // 在线人数采集
+	JOB_NEWS      = "news"      // 公告采集
+	JOB_PRICES    = "prices"    // 批量刷新价格
+	JOB_DISCOVERY = "discovery" // 发现候选游戏
+)
+
+// 失败的采集项
+const (
+	STAGE_DETAIL  = "detail"  // 详情和价格
+	STAGE_NEWS    = "news"    // 公告
+	STAGE_REVIEW  = "review"  // 评测汇总
+	STAGE_PLAYERS = "players" // 在线人数
+)
+
+// 任务定义
+type jobDef struct {
+	single bool                                                       // 是否支持只采集单个游戏
+	run    func(s gameService, run *jobRun, gameList []models.GameID) // 执行任务, 全部游戏时 gameList 为 nil
+}
+
+var jobDefList = map[string]jobDef{
+	JOB_COLLECT:   {single: true, run: gameService.collectGames},
+	JOB_PLAYERS:   {single: true, run: gameService.collectPlayers},
+	JOB_NEWS:      {single: true, run: gameService.collectNews},
+	JOB_PRICES:    {run: gameService.refreshPrices},
+	JOB_DISCOVERY: {run: gameService.discoverGames},
+}
+
+// jobRun 一次任务执行, 记录进度并等待游戏任务结束
+type jobRun struct {
+	name   string
+	single bool              // 单个游戏的任务不经过线程池, 避免排在全量采集之后
+	result *models.JobResult // 由 jobLock 保护
+	wg     sync.WaitGroup
+}
+
+// 保留的单个游戏任务状态数, 超出后移除最早结束的
+const maxSingleJobStatus = 100
+
+var jobLock sync.Mutex
+var jobStatusMap = make(map[string]*models.JobStatus)
+var singleJobList []string // 已结束的单个游戏任务, 按结束时间排序
+
+// 采集失败的游戏只保存在内存中, 重启后清空, 重启后的下一轮采集会重新记录仍然失败的游戏
+// 记录数不超过 游戏数 x 商店数 x 采集项, 成功后移除
+var failedLock sync.Mutex
+var failedGameMap = make(map[string]*models.FailedGame)
+
+// 开始任务, 同名任务正在执行时返回 nil
+func beginJob(name string, single bool) *jobRun {
+	jobLock.Lock()
+	defer jobLock.Unlock()
+	status, exist := jobStatusMap[name]
+	if !exist {
+		status = &models.JobStatus{Name: name}
+		jobStatusMap[name] = status
+	}
+	if status.Running {
+		return nil
+	}
+	run := &jobRun{name: name, single: single, result: &models.JobResult{StartTime: cm.LocalTime(time.Now())}}
+	status.Running, status.Current = true, run.result
+	if single {
+		singleJobList = removeJobName(singleJobList, name)
+	}
+	return run
+}
+
+func removeJobName(list []string, name string) []string {
+	for i, v := range list {
+		if v == name {
+			return append(list[:i], list[i+1:]...)
+		}
+	}
+	return list
+}
+
+// 结束任务, 等待游戏任务结束后记录结果
+func (r *jobRun) finish() {
+	r.wg.Wait()
+	jobLock.Lock()
+	defer jobLock.Unlock()
+	r.result.EndTime = cm.LocalTime(time.Now())
+	status := jobStatusMap[r.name]
+	status.Running, status.Current, status.Last = false, nil, r.result
+
+	// 单个游戏的任务按游戏记录状态, 只保留最近结束的, 避免状态列表无限增长
+	if r.single {
+		singleJobList = append(singleJobList, r.name)
+		for len(singleJobList) > maxSingleJobStatus {
+			delete(jobStatusMap, singleJobList[0])
+			singleJobList = singleJobList[1:]
+		}
+	}
+}
+
+// 记录任务整体失败原因
+func (r *jobRun) setError(msg string) {
+	jobLock.Lock()
+	defer jobLock.Unlock()
+	r.result.Error = msg
+}
+
+// 执行游戏任务, fn 结束时需调用 done
+func (r *jobRun) goGame(fn func()) {
+	r.wg.Add(1)
+	jobLock.Lock()
+	r.result.Total++
+	jobLock.Unlock()
+	if r.single {
+		go fn()
+	} else {
+		gameThread.Go(fn)
+	}
+}
+
+// 游戏任务结束
+func (r *jobRun) done() { r.wg.Done() }
+
+// 记录采集失败的游戏
+func (r *jobRun) fail(stage string, store string, gameID models.GameID, msg string) {
+	jobLock.Lock()
+	r.result.Failed++
+	jobLock.Unlock()
+
+	now := cm.LocalTime(time.Now())
+	key := failedGameKey(stage, store, gameID)
+	failedLock.Lock()
+	defer failedLock.Unlock()
+	v, exist := failedGameMap[key]
+	if !exist {
+		v = &models.FailedGame{GameID: gameID.ID, Store: store, Stage: stage, FirstTime: now}
+		failedGameMap[key] = v
+	}
+	v.Error, v.LastTime = msg, now
+	v.Count++
+}
+
+// 采集成功, 移除失败记录
+func (r *jobRun) succeed(stage string, store string, gameID models.GameID) {
+	failedLock.Lock()
+	defer failedLock.Unlock()
+	delete(failedGameMap, failedGameKey(stage, store, gameID))
+}
+
+func failedGameKey(stage string, store string, gameID models.GameID) string {
+	return stage + ":" + store + ":" + util.Int642String(gameID.ID)
+}
+
+// 执行任务, gameID 为 0 时采集全部游戏
+// async 为 true 时开始执行后立即返回, 同名任务正在执行时返回错误
+func (s gameService) runJob(name string, gameID int64, async bool) common.GFError {
+	def, exist := jobDefList[name]
+	if !exist {
+		return common.NewServiceError("未知的任务: " + name)
+	}
+
+	var gameList []models.GameID
+	key := name
+	if gameID != 0 {
+		if !def.single {
+			return common.NewServiceError("任务 " + name + " 不支持单个游戏")
+		}
+		game, gfErr := dao.GetGameDao().GetGameByID(gameID)
+		if gfErr != nil {
+			return gfErr
+		}
+		gameList = []models.GameID{game}
+		key = name + ":" + util.Int642String(gameID)
+	}
+
+	run := beginJob(key, gameID != 0)
+	if run == nil {
+		return common.NewServiceError("任务 " + key + " 正在执行")
+	}
+	exec := func() {
+		defer run.finish()
+		defer func() {
+			if err := recover(); err != nil {
+				log.Error("receive runJob recover, job=", key, " err:", err)
+				run.setError(fmt.Sprint(err))
+			}
+		}()
+
+		// 每次采集都查寻数据库 保证热更新
+		if gameList == nil && name != JOB_DISCOVERY {
+			var gfErr common.GFError
+			if gameList, gfErr = addAllGameToList(); gfErr != nil {
+				run.setError(gfErr.GetMsg())
+				return
+			}
+		}
+		def.run(s, run, gameList)
+	}
+
+	if async {
+		go exec()
+	} else {
+		exec()
+	}
+	return nil
+}
+
+// StartJob 异步执行任务, gameID 为 0 时采集全部游戏
+func (s gameService) StartJob(name string, gameID int64) common.GFError {
+	return s.runJob(name, gameID, true)
+}
+
+// GetJobStatusList 获取各任务的执行状态, 单个游戏的任务只保留最近结束的 maxSingleJobStatus 个
+func (s gameService) GetJobStatusList() []models.JobStatus {
+	jobLock.Lock()
+	defer jobLock.Unlock()
+	res := make([]models.JobStatus, 0, len(jobStatusMap))
+	for _, v := range jobStatusMap {
+		status := models.JobStatus{Name: v.Name, Running: v.Running}
+		// 复制结果, 避免返回后被修改
+		if v.Current != nil {
+			current := *v.Current
+			status.Current = &current
+		}
+		if v.Last != nil {
+			last := *v.Last
+			status.Last = &last
+		}
+		res = append(res, status)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// GetFailedGameList 获取采集失败的游戏, 按最近失败时间倒序, 只包含本次启动后的失败记录
+func (s gameService) GetFailedGameList() []models.FailedGame {
+	failedLock.Lock()
+	defer failedLock.Unlock()
+	res := make([]models.FailedGame, 0, len(failedGameMap))
+	for _, v := range failedGameMap {
+		res = append(res, *v)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return time.Time(res[i].LastTime).After(time.Time(res[j].LastTime))
+	})
+	return res
+}
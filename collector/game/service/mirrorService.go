@@ -0,0 +1,88 @@
+package service
+
+import (
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/mirror"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+)
+
+// 镜像地址, 未开启镜像、不是网络地址或镜像失败时返回原地址
+func mirrorURL(m *mirror.Mirror, gameID models.GameID, src string) string {
+	if !needMirror(m, src) {
+		return src
+	}
+
+	var media mirror.Media
+	var err error
+	if isHLS(src) {
+		media, err = m.FetchHLS(src)
+	} else {
+		media, err = m.Fetch(src)
+	}
+	if err != nil {
+		log.Warn("镜像媒体失败, game_id=", gameID.ID, " err:", err)
+		return src
+	}
+	return media.URL
+}
+
+// 镜像图片并生成缩略图等信息, 失败时返回原地址
+func mirrorImage(m *mirror.Mirror, gameID models.GameID, src string) (string, *models.ImageMeta) {
+	if !needMirror(m, src) {
+		return src, nil
+	}
+	media, err := m.FetchImage(src)
+	if err != nil {
+		log.Warn("镜像图片失败, game_id=", gameID.ID, " err:", err)
+		return src, nil
+	}
+	return media.URL, media.Image
+}
+
+// 是否需要镜像, 只镜像未镜像过的网络地址
+func needMirror(m *mirror.Mirror, src string) bool {
+	if m == nil || m.IsMirrored(src) {
+		return false
+	}
+	return strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://")
+}
+
+// 是否为 HLS 播放列表
+func isHLS(src string) bool {
+	u, err := url.Parse(src)
+	return err == nil && path.Ext(u.Path) == ".m3u8"
+}
+
+// 将封面、截图和预告片替换为镜像地址, 封面和截图同时生成缩略图等信息
+// 预告片只镜像 HLS 视频, DASH 和外部嵌入地址保持不变
+func mirrorGameMedia(gameID models.GameID, dbRecord *models.GfgGameRecord, redisRecord *models.GameSaveModel) {
+	m := mirror.GetMirror()
+	if m == nil {
+		return
+	}
+
+	redisRecord.HeaderImage, redisRecord.HeaderImageMeta = mirrorImage(m, gameID, redisRecord.HeaderImage)
+	dbRecord.Cover = redisRecord.HeaderImage
+
+	screenshots := make([]models.SteamAppScreenshot, len(redisRecord.Screenshots))
+	for i, v := range redisRecord.Screenshots {
+		v.PathThumbnail = mirrorURL(m, gameID, v.PathThumbnail)
+		v.PathFull, v.Image = mirrorImage(m, gameID, v.PathFull)
+		screenshots[i] = v
+	}
+	redisRecord.Screenshots = screenshots
+
+	movies := make([]models.SteamAppMovie, len(redisRecord.Movies))
+	for i, v := range redisRecord.Movies {
+		v.Thumbnail = mirrorURL(m, gameID, v.Thumbnail)
+		if m.Video() {
+			v.HlsH264 = mirrorURL(m, gameID, v.HlsH264)
+		}
+		movies[i] = v
+	}
+	redisRecord.Movies = movies
+}
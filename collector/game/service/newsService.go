@@ -0,0 +1,281 @@
+package service
+
+import (
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	cs "github.com/GoFurry/gofurry-game-collector/common/service"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/bytedance/sonic"
+)
+
+// 最新公告列表的篇数
+const newsViewCount = 10
+
+// 翻页采集时每页的公告篇数
+const newsPageCount = 20
+
+// 增量采集最多翻页数
+const newsIncrementalMaxPage = 10
+
+// 全量补采最多翻页数
+const newsBackfillMaxPage = 500
+
+// 增量采集公告, 支持翻页的数据源从最新公告向前翻页, 直到遇到上次采集的最新公告
+func collectGameNews(src Source, gameID models.GameID) common.GFError {
+	pager, ok := src.(NewsPager)
+	if !ok {
+		newsRes, gfErr := src.FetchNews(gameID, newsViewCount)
+		if gfErr != nil {
+			return gfErr
+		}
+		saveGameNews(src, gameID, newsRes, true, time.Time{})
+		refreshGameNewsView(src, gameID, util.SortedKeys(newsRes))
+		return nil
+	}
+
+	cursor, gfErr := dao.GetGameNewsDao().GetNewsCursor(gameID.ID, src.Name())
+	if gfErr != nil && gfErr.GetMsg() != "record not found" {
+		return gfErr
+	} else if gfErr != nil {
+		// 新添加的游戏没有任何公告时全量补采
+		cnt, gfErr := dao.GetGameNewsDao().CountGameNews(gameID.ID, src.Name())
+		if gfErr != nil {
+			return gfErr
+		}
+		if cnt == 0 {
+			return backfillGameNews(src, gameID)
+		}
+	}
+
+	// 没有游标时(升级前已有旧公告的游戏)只保存不通知, 避免把已有的公告当作新公告通知
+	// 有游标时只通知比上次最新公告更晚发布的公告
+	last := cursor
+	notify := cursor.ID != 0
+	langSet := make(map[string]bool)
+	pageCursor := ""
+	for page := 0; page < newsIncrementalMaxPage; page++ {
+		newsRes, next, gfErr := pager.FetchNewsPage(gameID, pageCursor, newsPageCount)
+		if gfErr != nil {
+			return gfErr
+		}
+		saveGameNews(src, gameID, newsRes, notify, time.Time(last.LastPostTime))
+		updateNewsCursor(&cursor, newsRes)
+		for lang := range newsRes {
+			langSet[lang] = true
+		}
+
+		// 没有游标时只采集第一页, 翻到上次最新公告或没有更多公告时结束
+		if last.ID == 0 || reachNewsCursor(newsRes, last) || next == "" {
+			break
+		}
+		pageCursor = next
+	}
+
+	saveNewsCursor(src, gameID, &cursor)
+	refreshGameNewsView(src, gameID, util.SortedKeys(langSet))
+	return nil
+}
+
+// 全量补采游戏的历史公告, 不发送新公告通知
+func backfillGameNews(src Source, gameID models.GameID) common.GFError {
+	pager, ok := src.(NewsPager)
+	if !ok {
+		return common.NewServiceError(common.RETURN_NOT_SUPPORTED)
+	}
+
+	cursor, gfErr := dao.GetGameNewsDao().GetNewsCursor(gameID.ID, src.Name())
+	if gfErr != nil && gfErr.GetMsg() != "record not found" {
+		return gfErr
+	}
+
+	total := 0
+	langSet := make(map[string]bool)
+	pageCursor := ""
+	for page := 0; page < newsBackfillMaxPage; page++ {
+		newsRes, next, gfErr := pager.FetchNewsPage(gameID, pageCursor, newsPageCount)
+		if gfErr != nil {
+			return gfErr
+		}
+		total += saveGameNews(src, gameID, newsRes, false, time.Time{})
+		updateNewsCursor(&cursor, newsRes)
+		for lang := range newsRes {
+			langSet[lang] = true
+		}
+
+		if next == "" {
+			break
+		}
+		pageCursor = next
+	}
+
+	cursor.BackfillTime = cm.LocalTime(time.Now())
+	saveNewsCursor(src, gameID, &cursor)
+	refreshGameNewsView(src, gameID, util.SortedKeys(langSet))
+	log.Info(src.Name(), " 公告补采结束, game_id=", gameID.ID, " 新增 ", total, " 篇")
+	return nil
+}
+
+// 页中是否包含上次采集的最新公告或更早的公告
+func reachNewsCursor(newsRes map[string][]models.SteamAppNews, cursor models.GfgGameNewsCursor) bool {
+	for _, newsList := range newsRes {
+		for _, news := range newsList {
+			if news.Gid == "" {
+				continue
+			}
+			if news.Gid == cursor.LastGid || !time.Time(news.Date).After(time.Time(cursor.LastPostTime)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 用页中最新的公告更新游标
+func updateNewsCursor(cursor *models.GfgGameNewsCursor, newsRes map[string][]models.SteamAppNews) {
+	for _, newsList := range newsRes {
+		for _, news := range newsList {
+			if news.Gid == "" {
+				continue
+			}
+			if cursor.LastGid == "" || time.Time(news.Date).After(time.Time(cursor.LastPostTime)) {
+				cursor.LastGid, cursor.LastPostTime = news.Gid, news.Date
+			}
+		}
+	}
+}
+
+// 保存公告采集游标
+func saveNewsCursor(src Source, gameID models.GameID, cursor *models.GfgGameNewsCursor) {
+	if cursor.ID == 0 {
+		cursor.ID = util.GenerateId()
+	}
+	cursor.GameID, cursor.Store = gameID.ID, src.Name()
+	cursor.UpdateTime = cm.LocalTime(time.Now())
+	if err := dao.GetGameNewsDao().SaveNewsCursor(cursor); err != nil {
+		log.Error("SaveNewsCursor error: ", err.GetMsg())
+	}
+}
+
+// 按商店公告id保存公告, 已有的公告保留采集时间, 返回新增的公告数
+// notify 为 true 时对新公告发送通知, 首次采集时不通知, since 不为零值时只通知晚于该时间发布的公告
+func saveGameNews(src Source, gameID models.GameID, newsRes map[string][]models.SteamAppNews, notify bool, since time.Time) (added int) {
+	oldCount, gfErr := dao.GetGameNewsDao().CountGameNews(gameID.ID, src.Name())
+	if gfErr != nil {
+		log.Error("CountGameNews error: ", gfErr.GetMsg())
+	}
+	notified := make(map[string]bool)
+
+	for _, lang := range util.SortedKeys(newsRes) {
+		for i, news := range newsRes[lang] {
+			// steam 更新公告接口若不足 num 篇公告就返回空
+			if news.Gid == "" || (news.Title == "" && news.Contents == "") {
+				continue
+			}
+
+			saveModel := models.GfgGameNews{
+				ID:         util.GenerateId(),
+				GameID:     gameID.ID,
+				Gid:        news.Gid,
+				Headline:   news.Title,
+				Content:    sanitizeHTML(news.Contents),
+				ContentRaw: news.Contents,
+				Index:      int64(i),
+				PostTime:   news.Date,
+				Author:     news.Author,
+				URL:        news.URL,
+				Total:      news.Count,
+				Lang:       lang,
+				Store:      src.Name(),
+			}
+
+			// 储存到数据库, 没有公告id的旧记录按地址补齐公告id
+			record, err := dao.GetGameNewsDao().GetGameNewsByGid(gameID.ID, lang, src.Name(), news.Gid)
+			if err != nil && err.GetMsg() == "record not found" && news.URL != "" {
+				record, err = dao.GetGameNewsDao().GetLegacyGameNewsByURL(gameID.ID, lang, src.Name(), news.URL)
+			}
+			if err != nil && err.GetMsg() == "record not found" {
+				saveModel.CreateTime = cm.LocalTime(time.Now())
+				// 数据源没有发布时间时记录首次采集时间, 按列表顺序错开一秒, 保证最新公告列表的顺序
+				if time.Time(news.Date).IsZero() {
+					saveModel.PostTime = cm.LocalTime(time.Now().Add(-time.Duration(i) * time.Second))
+				}
+				inserted, err := dao.GetGameNewsDao().AddNews(&saveModel)
+				if err != nil {
+					log.Error("AddNews error: ", err.GetMsg())
+					continue
+				}
+				// 其他采集任务已同时保存该公告, 由该任务通知
+				if !inserted {
+					continue
+				}
+				added++
+
+				// 新公告通知, 中英文公告只通知一次
+				if notify && oldCount > 0 && !notified[news.Gid] && (since.IsZero() || time.Time(news.Date).After(since)) {
+					notified[news.Gid] = true
+					emitEvent(models.GfgGameEvent{
+						GameID:     gameID.ID,
+						Store:      src.Name(),
+						EventType:  models.EVENT_NEWS_POSTED,
+						Detail:     news.Title,
+						URL:        news.URL,
+						CreateTime: saveModel.PostTime,
+					})
+				}
+			} else if err == nil {
+				saveModel.CreateTime = record.CreateTime
+				saveModel.ID = record.ID
+				if time.Time(news.Date).IsZero() {
+					saveModel.PostTime = record.PostTime
+				}
+				dao.GetGameNewsDao().Update(record.ID, &saveModel)
+			} else {
+				log.Error("GetGameNewsByGid error: ", err.GetMsg())
+			}
+		}
+	}
+	return
+}
+
+// 按发布时间刷新 redis 中最新的公告列表 <prefix><lang>-news<id>-<idx>
+func refreshGameNewsView(src Source, gameID models.GameID, langList []string) {
+	idStr := util.Int642String(gameID.ID)
+	keyPrefix := redisKeyPrefix(src, gameID)
+	for _, lang := range langList {
+		newsList, err := dao.GetGameNewsDao().GetLatestGameNews(gameID.ID, lang, src.Name(), newsViewCount)
+		if err != nil {
+			log.Error("GetLatestGameNews error: ", err.GetMsg())
+			continue
+		}
+		for i, news := range newsList {
+			idx := util.Int2String(i)
+			news.Index = int64(i)
+
+			jsonResult, _ := sonic.Marshal(news)
+			cs.SetNX(keyPrefix+lang+"-news"+idStr+"-"+idx, string(jsonResult), 168*time.Hour)     // 创建记录
+			cs.SetExpire(keyPrefix+lang+"-news"+idStr+"-"+idx, string(jsonResult), 168*time.Hour) // 更新记录
+		}
+	}
+}
+
+// BackfillNews 全量补采游戏在各数据源的历史公告
+func (s gameService) BackfillNews(id int64) common.GFError {
+	gameID, gfErr := dao.GetGameDao().GetGameByID(id)
+	if gfErr != nil {
+		return gfErr
+	}
+	for _, src := range GetSourceList() {
+		if !src.Supports(gameID) {
+			continue
+		}
+		if gfErr = backfillGameNews(src, gameID); gfErr != nil && !isNotSupported(gfErr) {
+			return gfErr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	cs "github.com/GoFurry/gofurry-game-collector/common/service"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/bytedance/sonic"
+)
+
+// 保存游戏的礼包和各国区礼包价格, 并写入 redis
+func saveGamePackages(src Source, gameID models.GameID, detail models.StoreDetail) {
+	fetcher, ok := src.(PackageFetcher)
+	if !ok {
+		return
+	}
+
+	packageRes, gfErr := fetcher.FetchPackages(detail.Packages)
+	if gfErr != nil {
+		log.Warn("FetchPackages 失败, game_id=", gameID.ID, " err:", gfErr.GetMsg())
+		return
+	}
+
+	now := cm.LocalTime(time.Now())
+	var packageList []models.GfgGamePackage
+	var priceList []models.GfgGamePackagePrice
+	var redisList []models.GamePackageModel
+	for _, packageID := range detail.Packages {
+		pkg, exist := packageRes[packageID]
+		if !exist {
+			continue
+		}
+
+		appsJson, _ := sonic.Marshal(pkg.Apps)
+		packageList = append(packageList, models.GfgGamePackage{
+			ID:         util.GenerateId(),
+			GameID:     gameID.ID,
+			PackageID:  packageID,
+			Name:       pkg.Name,
+			Apps:       string(appsJson),
+			AppCount:   int64(len(pkg.Apps)),
+			Sort:       int64(len(packageList)),
+			UpdateTime: now,
+		})
+		for _, cc := range util.SortedKeys(pkg.Prices) {
+			price := pkg.Prices[cc]
+			priceList = append(priceList, models.GfgGamePackagePrice{
+				ID:         util.GenerateId(),
+				PackageID:  packageID,
+				Region:     cc,
+				Currency:   price.Currency,
+				Initial:    price.Initial,
+				Final:      price.Final,
+				Discount:   price.DiscountPercent,
+				Individual: price.Individual,
+				UpdateTime: now,
+			})
+		}
+		redisList = append(redisList, models.GamePackageModel{
+			PackageID: packageID,
+			Name:      pkg.Name,
+			Apps:      pkg.Apps,
+			Prices:    pkg.Prices,
+		})
+	}
+
+	// 存数据库
+	if err := dao.GetGamePackageDao().ReplaceGamePackages(gameID.ID, packageList); err != nil {
+		log.Error("ReplaceGamePackages error: ", err.GetMsg())
+		return
+	}
+	if err := dao.GetGamePackageDao().SavePackagePrices(priceList); err != nil {
+		log.Error("SavePackagePrices error: ", err.GetMsg())
+	}
+
+	// 存 redis
+	idStr := util.Int642String(gameID.ID)
+	jsonResult, _ := sonic.Marshal(redisList)
+	cs.SetNX("game:packages"+idStr, string(jsonResult), 168*time.Hour)     // 创建记录
+	cs.SetExpire("game:packages"+idStr, string(jsonResult), 168*time.Hour) // 更新记录
+}
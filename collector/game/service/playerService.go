@@ -0,0 +1,137 @@
+package service
+
+import (
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	cs "github.com/GoFurry/gofurry-game-collector/common/service"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/GoFurry/gofurry-game-collector/roof/env"
+	"github.com/bytedance/sonic"
+)
+
+// 在线人数汇总级别, 每一级由上一级汇总而来
+var playerRollupLevels = []struct {
+	table  string // 汇总表
+	unit   string // 统计周期
+	source string // 数据来源表
+}{
+	{models.TableNameGfgGamePlayerHourly, "hour", models.TableNameGfgGamePlayerCount},
+	{models.TableNameGfgGamePlayerDaily, "day", models.TableNameGfgGamePlayerHourly},
+	{models.TableNameGfgGamePlayerMonthly, "month", models.TableNameGfgGamePlayerDaily},
+}
+
+// 未配置 collector.game.player_raw_retention 时原始记录保留的天数
+const defaultPlayerRawRetention = 30
+
+// RollupPlayerCount 汇总在线人数并清理过期的原始记录
+func (s gameService) RollupPlayerCount() {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Error("receive RollupPlayerCount recover: ", err)
+		}
+	}()
+
+	for _, level := range playerRollupLevels {
+		// 从最后一个统计周期开始重新汇总, 该周期可能尚未结束
+		start, err := dao.GetGamePlayerDao().GetLastBucketTime(level.table)
+		if err != nil {
+			log.Error("GetLastBucketTime error: ", err.GetMsg())
+			return
+		}
+
+		var list []models.PlayerRollup
+		if level.source == models.TableNameGfgGamePlayerCount {
+			list, err = dao.GetGamePlayerDao().RollupPlayerCount(level.unit, start)
+		} else {
+			list, err = dao.GetGamePlayerDao().RollupPlayerStat(level.source, level.unit, start)
+		}
+		if err != nil {
+			log.Error("rollup ", level.table, " error: ", err.GetMsg())
+			return
+		}
+		if err = dao.GetGamePlayerDao().SavePlayerRollup(level.table, list); err != nil {
+			log.Error("SavePlayerRollup ", level.table, " error: ", err.GetMsg())
+			return
+		}
+	}
+
+	// 更新在线人数峰值
+	updatePlayerPeak()
+
+	// 清理过期的原始记录, 汇总失败时不会执行到这里
+	retention := env.GetServerConfig().Collector.Game.PlayerRawRetention
+	if retention <= 0 {
+		retention = defaultPlayerRawRetention
+	}
+	cnt, err := dao.GetGamePlayerDao().DeletePlayerCountBefore(time.Now().AddDate(0, 0, -retention))
+	if err != nil {
+		log.Error("DeletePlayerCountBefore error: ", err.GetMsg())
+		return
+	}
+	log.Info("RollupPlayerCount 汇总结束, 清理原始记录 ", cnt, " 条")
+}
+
+// 计算各游戏 24 小时、30 天和历史在线人数峰值, 保存到数据库和 redis
+// 24 小时峰值取原始记录, 30 天取小时汇总, 历史峰值取月汇总
+func updatePlayerPeak() {
+	now := time.Now()
+	peakMap := make(map[int64]*models.GfgGamePlayerPeak)
+	getPeak := func(gameID int64) *models.GfgGamePlayerPeak {
+		if _, ok := peakMap[gameID]; !ok {
+			peakMap[gameID] = &models.GfgGamePlayerPeak{
+				ID:         util.GenerateId(),
+				GameID:     gameID,
+				UpdateTime: cm.LocalTime(now),
+			}
+		}
+		return peakMap[gameID]
+	}
+
+	list24h, err := dao.GetGamePlayerDao().GetPlayerPeakList(models.TableNameGfgGamePlayerCount, "count", "create_time", now.Add(-24*time.Hour))
+	if err != nil {
+		log.Error("GetPlayerPeakList 24h error: ", err.GetMsg())
+		return
+	}
+	for _, v := range list24h {
+		peak := getPeak(v.GameID)
+		peak.Peak24h, peak.Peak24hTime = v.Max, v.PeakTime
+	}
+
+	list30d, err := dao.GetGamePlayerDao().GetPlayerPeakList(models.TableNameGfgGamePlayerHourly, "max", "peak_time", now.AddDate(0, 0, -30))
+	if err != nil {
+		log.Error("GetPlayerPeakList 30d error: ", err.GetMsg())
+		return
+	}
+	for _, v := range list30d {
+		peak := getPeak(v.GameID)
+		peak.Peak30d, peak.Peak30dTime = v.Max, v.PeakTime
+	}
+
+	listAll, err := dao.GetGamePlayerDao().GetPlayerPeakList(models.TableNameGfgGamePlayerMonthly, "max", "peak_time", time.Time{})
+	if err != nil {
+		log.Error("GetPlayerPeakList all error: ", err.GetMsg())
+		return
+	}
+	for _, v := range listAll {
+		peak := getPeak(v.GameID)
+		peak.PeakAll, peak.PeakAllTime = v.Max, v.PeakTime
+	}
+
+	for gameID, peak := range peakMap {
+		// 存数据库
+		if err = dao.GetGamePlayerDao().SavePlayerPeak(peak); err != nil {
+			log.Error("SavePlayerPeak error: ", err.GetMsg())
+			continue
+		}
+
+		// 存 redis, 与 game:online<id> 并列
+		idStr := util.Int642String(gameID)
+		jsonResult, _ := sonic.Marshal(peak)
+		cs.SetNX("game:online-peak"+idStr, string(jsonResult), 168*time.Hour)     // 创建记录
+		cs.SetExpire("game:online-peak"+idStr, string(jsonResult), 168*time.Hour) // 更新记录
+	}
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	cs "github.com/GoFurry/gofurry-game-collector/common/service"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/GoFurry/gofurry-game-collector/roof/env"
+	"github.com/bytedance/sonic"
+)
+
+// 未配置 collector.game.regions 时默认采集的国区
+var defaultRegionList = []env.RegionConfig{
+	{CC: "CN", AcceptLanguage: common.ACCEPT_LANGUAGE_CN, Lang: "zh", Currency: "CNY"},
+	{CC: "HK", AcceptLanguage: common.ACCEPT_LANGUAGE_CN, Currency: "HKD"},
+	{CC: "US", AcceptLanguage: common.ACCEPT_LANGUAGE_EN, Lang: "en", Currency: "USD"},
+}
+
+// 获取采集的国区
+func getRegionList() []env.RegionConfig {
+	if regions := env.GetServerConfig().Collector.Game.Regions; len(regions) > 0 {
+		return regions
+	}
+	return defaultRegionList
+}
+
+// 获取国区配置
+func getRegion(cc string) (env.RegionConfig, bool) {
+	for _, v := range getRegionList() {
+		if v.CC == cc {
+			return v, true
+		}
+	}
+	return env.RegionConfig{}, false
+}
+
+// 获取记录语言对应的价格国区
+func getLangRegion(lang string) (env.RegionConfig, bool) {
+	for _, v := range getRegionList() {
+		if v.Lang != "" && v.Lang == lang {
+			return v, true
+		}
+	}
+	return env.RegionConfig{}, false
+}
+
+// 免费游戏的价格展示
+func freeText(lang string) string {
+	if lang == "zh" {
+		return "免费"
+	}
+	return "free"
+}
+
+// 价格列表, 免费游戏只有一项
+func buildPriceList(priceRes map[string]models.SteamAppPrice, isFree bool) string {
+	var priceList []models.PriceModel
+	if isFree {
+		priceList = append(priceList, models.PriceModel{
+			Price:   "free",
+			Country: "免费",
+		})
+	} else {
+		for _, k := range util.SortedKeys(priceRes) {
+			priceList = append(priceList, models.PriceModel{
+				Price:   priceRes[k].FinalFormatted,
+				Country: k,
+			})
+		}
+	}
+	priceListJson, jsonErr := sonic.Marshal(priceList)
+	if jsonErr != nil {
+		log.Error("marshal priceList error: ", jsonErr)
+	}
+	return string(priceListJson)
+}
+
+// 记录语言对应国区的价格
+func langPrice(lang string, priceRes map[string]models.SteamAppPrice, isFree bool) (models.SteamAppPrice, bool) {
+	priceRegion, hasRegion := getLangRegion(lang)
+	if isFree {
+		return models.SteamAppPrice{
+			Currency:         priceRegion.Currency,
+			InitialFormatted: freeText(lang),
+			FinalFormatted:   freeText(lang),
+		}, true
+	}
+	price, exist := priceRes[priceRegion.CC]
+	return price, hasRegion && exist
+}
+
+// 按 商店:游戏id:国区 加锁的价格写入锁
+// 价格刷新任务和完整采集任务可能同时保存同一游戏的价格, 读取上一条历史、写入历史和检测事件需要串行, 否则会重复记录历史和事件
+var priceLockMap sync.Map
+
+// 获取游戏国区的价格写入锁
+func priceLock(store string, gameID int64, cc string) *sync.Mutex {
+	lock, _ := priceLockMap.LoadOrStore(store+":"+util.Int642String(gameID)+":"+cc, new(sync.Mutex))
+	return lock.(*sync.Mutex)
+}
+
+// 保存各国区价格到数据库和 redis
+func saveGamePrice(src Source, gameID models.GameID, priceRes map[string]models.SteamAppPrice, isFree bool) {
+	keyPrefix := redisKeyPrefix(src, gameID)
+	for cc, price := range priceRes {
+		saveRegionPrice(src, gameID, cc, price, isFree, keyPrefix)
+	}
+}
+
+// 保存单个国区的价格、价格历史和史低, 并检测打折事件
+func saveRegionPrice(src Source, gameID models.GameID, cc string, price models.SteamAppPrice, isFree bool, keyPrefix string) {
+	lock := priceLock(src.Name(), gameID.ID, cc)
+	lock.Lock()
+	defer lock.Unlock()
+
+	region, _ := getRegion(cc)
+	if price.Currency == "" {
+		price.Currency = region.Currency
+	}
+	if isFree {
+		price.InitialFormatted, price.FinalFormatted = freeText(region.Lang), freeText(region.Lang)
+	}
+
+	record := models.GfgGamePrice{
+		ID:               util.GenerateId(),
+		GameID:           gameID.ID,
+		Store:            src.Name(),
+		Region:           cc,
+		Currency:         price.Currency,
+		Initial:          price.Initial,
+		Final:            price.Final,
+		Discount:         price.DiscountPercent,
+		InitialFormatted: price.InitialFormatted,
+		FinalFormatted:   price.FinalFormatted,
+		UpdateTime:       cm.LocalTime(time.Now()),
+	}
+
+	// 存数据库
+	oldRecord, err := dao.GetGamePriceDao().GetGamePrice(gameID.ID, src.Name(), cc)
+	if err == nil {
+		record.ID = oldRecord.ID
+	} else if err.GetMsg() != "record not found" {
+		log.Error("GetGamePrice error: ", err.GetMsg())
+		return
+	}
+	if err = dao.GetGamePriceDao().SavePrice(&record); err != nil {
+		log.Error("SavePrice error: ", err.GetMsg())
+	}
+
+	// 存 redis
+	idStr := util.Int642String(gameID.ID)
+	jsonResult, _ := sonic.Marshal(record)
+	cs.SetNX(keyPrefix+cc+"-price"+idStr, string(jsonResult), 168*time.Hour)     // 创建记录
+	cs.SetExpire(keyPrefix+cc+"-price"+idStr, string(jsonResult), 168*time.Hour) // 更新记录
+
+	// 价格历史和史低
+	last, changed := recordPriceHistory(record)
+	updatePriceLow(record, keyPrefix)
+
+	// 已有历史时检测打折和降价事件
+	if changed && last.ID != 0 {
+		detectSaleEvent(last, record)
+	}
+}
+
+// 价格或折扣变化时写入价格历史, 返回变化前的价格
+func recordPriceHistory(record models.GfgGamePrice) (last models.GfgGamePriceHistory, changed bool) {
+	last, err := dao.GetGamePriceDao().GetLastPriceHistory(record.GameID, record.Store, record.Region)
+	if err != nil && err.GetMsg() != "record not found" {
+		log.Error("GetLastPriceHistory error: ", err.GetMsg())
+		return last, false
+	}
+	// 与上一条历史相同则不记录
+	if err == nil && last.Initial == record.Initial && last.Final == record.Final &&
+		last.Discount == record.Discount && last.Currency == record.Currency {
+		return last, false
+	}
+
+	history := models.GfgGamePriceHistory{
+		ID:         util.GenerateId(),
+		GameID:     record.GameID,
+		Store:      record.Store,
+		Region:     record.Region,
+		Currency:   record.Currency,
+		Initial:    record.Initial,
+		Final:      record.Final,
+		Discount:   record.Discount,
+		CreateTime: record.UpdateTime,
+	}
+	if err = dao.GetGamePriceDao().Add(&history); err != nil {
+		log.Error("add GfgGamePriceHistory error: ", err.GetMsg())
+		return last, false
+	}
+	return last, true
+}
+
+// 更新国区史低价格
+func updatePriceLow(record models.GfgGamePrice, keyPrefix string) {
+	// 没有价格信息时跳过
+	if record.Initial == 0 && record.Final == 0 {
+		return
+	}
+
+	low, err := dao.GetGamePriceDao().GetPriceLow(record.GameID, record.Store, record.Region)
+	if err != nil && err.GetMsg() != "record not found" {
+		log.Error("GetPriceLow error: ", err.GetMsg())
+		return
+	}
+	// 未创建史低、出现更低价格或货币变化时更新
+	if err == nil && low.Currency == record.Currency && low.Final <= record.Final {
+		return
+	}
+
+	if err != nil {
+		low.ID = util.GenerateId()
+	}
+	low.GameID, low.Store, low.Region = record.GameID, record.Store, record.Region
+	low.Currency = record.Currency
+	low.Initial, low.Final, low.Discount = record.Initial, record.Final, record.Discount
+	low.LowTime, low.UpdateTime = record.UpdateTime, record.UpdateTime
+	if err = dao.GetGamePriceDao().SavePriceLow(&low); err != nil {
+		log.Error("SavePriceLow error: ", err.GetMsg())
+		return
+	}
+
+	// 存 redis
+	idStr := util.Int642String(record.GameID)
+	jsonResult, _ := sonic.Marshal(low)
+	cs.SetExpire(keyPrefix+record.Region+"-low"+idStr, string(jsonResult), 0) // 史低不过期
+}
+
+// RefreshPrices 批量刷新价格, 只更新价格、价格历史和记录中的价格字段
+func (s gameService) RefreshPrices() {
+	if gfErr := s.runJob(JOB_PRICES, 0, false); gfErr != nil {
+		log.Warn("RefreshPrices 跳过: ", gfErr.GetMsg())
+	}
+}
+
+// 批量刷新全部游戏的价格
+func (s gameService) refreshPrices(run *jobRun, gameList []models.GameID) {
+	log.Info("RefreshPrices 价格刷新开始")
+	for _, src := range GetSourceList() {
+		batcher, ok := src.(PriceBatcher)
+		if !ok {
+			continue
+		}
+		srcGameList := filterGameList(src, gameList)
+		priceRes, gfErr := batcher.FetchPriceBatch(srcGameList)
+		if gfErr != nil {
+			// 已获取的部分照常保存
+			log.Warn(src.Name(), " FetchPriceBatch 失败: ", gfErr.GetMsg())
+			run.setError(src.Name() + " FetchPriceBatch 失败: " + gfErr.GetMsg())
+		}
+		for _, v := range srcGameList {
+			if prices, exist := priceRes[v.ID]; exist {
+				refreshGamePrice(src, v, prices)
+			}
+		}
+	}
+	log.Info("RefreshPrices 价格刷新结束")
+}
+
+// 保存单个游戏刷新后的价格
+// 所有国区都没有价格时可能是免费或未发售, 交给完整采集处理
+func refreshGamePrice(src Source, gameID models.GameID, priceRes map[string]models.SteamAppPrice) {
+	hasPrice := false
+	for _, v := range priceRes {
+		hasPrice = hasPrice || v.Currency != "" || v.FinalFormatted != ""
+	}
+	if !hasPrice {
+		return
+	}
+
+	// 国区价格和价格历史
+	saveGamePrice(src, gameID, priceRes, false)
+
+	// 各语言记录中的价格
+	priceListStr := buildPriceList(priceRes, false)
+	idStr := util.Int642String(gameID.ID)
+	keyPrefix := redisKeyPrefix(src, gameID)
+	for _, v := range getLanguageList() {
+		price, exist := langPrice(v.Lang, priceRes, false)
+		if !exist {
+			continue
+		}
+		if err := dao.GetGameDao().UpdateGameRecordPrice(gameID.ID, v.Lang, src.Name(), priceListStr, price.Initial, price.Final, price.DiscountPercent); err != nil {
+			log.Error("UpdateGameRecordPrice error: ", err.GetMsg())
+		}
+
+		// redis 记录不存在时等待完整采集创建
+		key := keyPrefix + v.Lang + "-info" + idStr
+		val, gfErr := cs.GetString(key)
+		if gfErr != nil || val == "" {
+			continue
+		}
+		var redisRecord models.GameSaveModel
+		if jsonErr := sonic.UnmarshalString(val, &redisRecord); jsonErr != nil {
+			log.Error("unmarshal GameSaveModel error: ", jsonErr)
+			continue
+		}
+		redisRecord.Price, redisRecord.PriceList = price, priceListStr
+		jsonResult, _ := sonic.Marshal(redisRecord)
+		cs.SetExpire(key, string(jsonResult), 168*time.Hour) // 更新记录
+	}
+}
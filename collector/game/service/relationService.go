@@ -0,0 +1,105 @@
+package service
+
+import (
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	cs "github.com/GoFurry/gofurry-game-collector/common/service"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/bytedance/sonic"
+)
+
+// 关联应用基本信息的刷新间隔, 价格每次采集都更新
+const relatedAppRefresh = 7 * 24 * time.Hour
+
+// 保存游戏的 DLC、原声音乐、试玩版和本体关联, 并写入 redis
+func saveGameRelations(src Source, gameID models.GameID, detail models.StoreDetail) {
+	fetcher, ok := src.(RelationFetcher)
+	if !ok {
+		return
+	}
+
+	// 关联的 appid 和类型, 同一 appid 只保留第一次出现的类型
+	var appids []int64
+	relations := make(map[int64]string)
+	addRelation := func(appid int64, relation string) {
+		if _, exist := relations[appid]; exist || appid == 0 || appid == gameID.Appid {
+			return
+		}
+		appids = append(appids, appid)
+		relations[appid] = relation
+	}
+	addRelation(detail.FullGame, models.RELATION_FULLGAME)
+	for _, v := range detail.Demos {
+		addRelation(v, models.RELATION_DEMO)
+	}
+	for _, v := range detail.DLC {
+		addRelation(v, models.RELATION_DLC)
+	}
+
+	oldList, err := dao.GetGameRelationDao().GetGameRelations(gameID.ID)
+	if err != nil {
+		log.Error("GetGameRelations error: ", err.GetMsg())
+		return
+	}
+	oldMap := make(map[int64]models.GfgGameRelation)
+	for _, v := range oldList {
+		oldMap[v.Appid] = v
+	}
+
+	priceRes, err := fetcher.FetchRelatedPrices(appids)
+	if err != nil {
+		log.Warn("FetchRelatedPrices 失败, game_id=", gameID.ID, " err:", err.GetMsg())
+		return
+	}
+
+	now := time.Now()
+	list := make([]models.GfgGameRelation, 0, len(appids))
+	for _, appid := range appids {
+		record, exist := oldMap[appid]
+		if !exist {
+			record = models.GfgGameRelation{ID: util.GenerateId(), GameID: gameID.ID, Appid: appid}
+		}
+
+		// 基本信息过期后重新采集, 失败时沿用旧信息
+		if !exist || now.Sub(time.Time(record.DetailTime)) > relatedAppRefresh {
+			app, gfErr := fetcher.FetchRelatedApp(appid)
+			if gfErr == nil {
+				record.Name, record.AppType, record.IsFree = app.Name, app.Type, app.IsFree
+				record.HeaderImage = app.HeaderImage
+				record.ReleaseDate, record.ComingSoon = app.ReleaseDate.Date, app.ReleaseDate.ComingSoon
+				record.DetailTime = cm.LocalTime(now)
+			} else {
+				log.Warn("FetchRelatedApp 失败, appid=", appid, " err:", gfErr.GetMsg())
+			}
+		}
+
+		// 原声音乐在商店中同样是 DLC, 按应用类型区分
+		record.Relation = relations[appid]
+		if record.Relation == models.RELATION_DLC && record.AppType == "music" {
+			record.Relation = models.RELATION_SOUNDTRACK
+		}
+
+		record.Prices = ""
+		if prices, ok := priceRes[appid]; ok {
+			jsonResult, _ := sonic.Marshal(prices)
+			record.Prices = string(jsonResult)
+		}
+		record.UpdateTime = cm.LocalTime(now)
+		list = append(list, record)
+	}
+
+	if err = dao.GetGameRelationDao().ReplaceGameRelations(gameID.ID, list); err != nil {
+		log.Error("ReplaceGameRelations error: ", err.GetMsg())
+		return
+	}
+
+	// 存 redis
+	idStr := util.Int642String(gameID.ID)
+	jsonResult, _ := sonic.Marshal(list)
+	cs.SetNX("game:relations"+idStr, string(jsonResult), 168*time.Hour)     // 创建记录
+	cs.SetExpire("game:relations"+idStr, string(jsonResult), 168*time.Hour) // 更新记录
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	cs "github.com/GoFurry/gofurry-game-collector/common/service"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/bytedance/sonic"
+)
+
+// startGameReviewCollect 开始游戏评测汇总采集, 每次采集记录一条历史
+// src 需实现 ReviewFetcher
+func startGameReviewCollect(run *jobRun, src Source, gameID models.GameID) func() {
+	return func() {
+		defer run.done() // 确保线程结束时组数减少
+		defer func() {
+			if err := recover(); err != nil {
+				log.Error("receive startGameReviewCollect recover, game_id=", gameID.ID, " err:", err)
+				run.fail(STAGE_REVIEW, src.Name(), gameID, fmt.Sprint(err))
+			}
+		}()
+
+		keyPrefix := redisKeyPrefix(src, gameID)
+		reviewRes, gfErr := src.(ReviewFetcher).FetchReviews(gameID)
+		if gfErr != nil {
+			log.Warn("FetchReviews 失败, game_id=", gameID.ID, " err:", gfErr.GetMsg())
+			run.fail(STAGE_REVIEW, src.Name(), gameID, gfErr.GetMsg())
+		} else {
+			run.succeed(STAGE_REVIEW, src.Name(), gameID)
+		}
+
+		// 存数据库, 部分语言失败时保存已采集的部分
+		now := cm.LocalTime(time.Now())
+		for _, lang := range util.SortedKeys(reviewRes) {
+			v := reviewRes[lang]
+			record := models.GfgGameReview{
+				ID:              util.GenerateId(),
+				GameID:          gameID.ID,
+				Lang:            lang,
+				TotalReviews:    v.All.TotalReviews,
+				TotalPositive:   v.All.TotalPositive,
+				TotalNegative:   v.All.TotalNegative,
+				ReviewScore:     v.All.ReviewScore,
+				ReviewScoreDesc: v.All.ReviewScoreDesc,
+				RecentReviews:   v.Recent.TotalReviews,
+				RecentPositive:  v.Recent.TotalPositive,
+				RecentNegative:  v.Recent.TotalNegative,
+				RecentScore:     v.Recent.ReviewScore,
+				RecentScoreDesc: v.Recent.ReviewScoreDesc,
+				CreateTime:      now,
+			}
+			if err := dao.GetGameReviewDao().Add(&record); err != nil {
+				log.Error("add GfgGameReview error: ", err.GetMsg())
+			}
+		}
+		if len(reviewRes) == 0 {
+			return
+		}
+
+		// 存 redis, 各语言取最新一次汇总
+		list, err := dao.GetGameReviewDao().GetLatestGameReview(gameID.ID)
+		if err != nil {
+			log.Error("GetLatestGameReview error: ", err.GetMsg())
+			return
+		}
+		reviewMap := make(map[string]models.GfgGameReview)
+		for _, v := range list {
+			reviewMap[v.Lang] = v
+		}
+		idStr := util.Int642String(gameID.ID)
+		jsonResult, _ := sonic.Marshal(reviewMap)
+		cs.SetNX(keyPrefix+"reviews"+idStr, string(jsonResult), 168*time.Hour)     // 创建记录
+		cs.SetExpire(keyPrefix+"reviews"+idStr, string(jsonResult), 168*time.Hour) // 更新记录
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/GoFurry/gofurry-game-collector/common/sanitize"
+	"github.com/GoFurry/gofurry-game-collector/roof/env"
+)
+
+var sanitizePolicy *sanitize.Policy
+var sanitizeOnce sync.Once
+
+// 获取富文本过滤策略, 未配置的项使用默认策略
+func getSanitizePolicy() *sanitize.Policy {
+	sanitizeOnce.Do(func() {
+		sanitizePolicy = sanitize.DefaultPolicy()
+		conf := env.GetServerConfig().Collector.Sanitize
+		if len(conf.Tags) > 0 {
+			sanitizePolicy.Tags = conf.Tags
+		}
+		if len(conf.Schemes) > 0 {
+			sanitizePolicy.Schemes = conf.Schemes
+		}
+		if len(conf.IframeHosts) > 0 {
+			sanitizePolicy.IframeHosts = conf.IframeHosts
+		}
+	})
+	return sanitizePolicy
+}
+
+// 入库前过滤富文本
+func sanitizeHTML(s string) string {
+	if s == "" {
+		return s
+	}
+	return getSanitizePolicy().Sanitize(s)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+)
+
+// Source 游戏商店数据源, 每个商店实现一个适配器
+type Source interface {
+	// Name 商店标识, 同时作为记录的 store 字段
+	Name() string
+	// Supports 该数据源是否负责采集此游戏
+	Supports(gameID models.GameID) bool
+	// FetchDetails 采集商店详情, key 为记录语言
+	FetchDetails(gameID models.GameID) (map[string]models.StoreDetail, common.GFError)
+	// FetchPrices 采集价格, key 为国区代码
+	FetchPrices(gameID models.GameID) (map[string]models.SteamAppPrice, common.GFError)
+	// FetchNews 采集最新 cnt 篇更新公告, key 为记录语言
+	FetchNews(gameID models.GameID, cnt int) (map[string][]models.SteamAppNews, common.GFError)
+	// FetchPlayerCount 采集当前在线人数, 不支持时返回 RETURN_NOT_SUPPORTED
+	FetchPlayerCount(gameID models.GameID) (int64, common.GFError)
+}
+
+// NewsPager 支持向前翻页采集公告的数据源
+type NewsPager interface {
+	// FetchNewsPage 从 cursor 开始向前采集 cnt 篇公告, cursor 为空时从最新公告开始
+	// 返回下一页游标, 没有更多公告时返回空游标
+	FetchNewsPage(gameID models.GameID, cursor string, cnt int) (map[string][]models.SteamAppNews, string, common.GFError)
+}
+
+// ReviewFetcher 支持采集用户评测汇总的数据源
+type ReviewFetcher interface {
+	// FetchReviews 按采集语言获取评测汇总, key 为记录语言
+	FetchReviews(gameID models.GameID) (map[string]models.ReviewSummary, common.GFError)
+}
+
+// RelationFetcher 支持采集 DLC、原声音乐和试玩版等关联应用的数据源
+type RelationFetcher interface {
+	// FetchRelatedApp 采集关联应用的基本信息
+	FetchRelatedApp(appid int64) (models.RelatedApp, common.GFError)
+	// FetchRelatedPrices 批量采集关联应用价格, key 为 appid 和国区代码
+	FetchRelatedPrices(appids []int64) (map[int64]map[string]models.SteamAppPrice, common.GFError)
+}
+
+// PackageFetcher 支持采集礼包价格的数据源
+type PackageFetcher interface {
+	// FetchPackages 按采集国区获取礼包名称、包含的应用和价格, key 为 packageid
+	FetchPackages(packageIDs []int64) (map[int64]models.StorePackage, common.GFError)
+}
+
+// PriceBatcher 支持批量采集价格的数据源, 用于只刷新价格的定时任务
+type PriceBatcher interface {
+	// FetchPriceBatch 批量获取各国区价格, key 为游戏表id和国区代码
+	FetchPriceBatch(gameList []models.GameID) (map[int64]map[string]models.SteamAppPrice, common.GFError)
+}
+
+// GameSearcher 支持按标签和关键词搜索商店的数据源, 用于发现候选游戏
+type GameSearcher interface {
+	// SearchGames 按标签id或关键词搜索游戏, 最多搜索 maxPages 页
+	SearchGames(tagID int64, keyword string, maxPages int) ([]models.SearchResult, common.GFError)
+}
+
+// 已注册的数据源
+var sourceList []Source
+
+func init() {
+	// Steam 为默认主数据源, 需最先注册
+	RegisterSource(steamSource{})
+	RegisterSource(itchSource{})
+	RegisterSource(gogSource{})
+}
+
+// RegisterSource 注册数据源, Collect 时按注册顺序遍历
+func RegisterSource(src Source) {
+	sourceList = append(sourceList, src)
+}
+
+// GetSourceList 获取已注册的数据源
+func GetSourceList() []Source { return sourceList }
+
+// 筛选数据源负责采集的游戏
+func filterGameList(src Source, gameList []models.GameID) (res []models.GameID) {
+	for _, v := range gameList {
+		if src.Supports(v) {
+			res = append(res, v)
+		}
+	}
+	return
+}
+
+// 是否为不支持的采集项
+func isNotSupported(err common.GFError) bool {
+	return err != nil && err.GetMsg() == common.RETURN_NOT_SUPPORTED
+}
+
+// 是否为游戏的主数据源, 即第一个负责该游戏的数据源
+func isPrimarySource(src Source, gameID models.GameID) bool {
+	for _, v := range sourceList {
+		if v.Supports(gameID) {
+			return v.Name() == src.Name()
+		}
+	}
+	return false
+}
+
+// redis 键前缀, 主数据源沿用 game:<lang>-info<id> 格式, 其他数据源加上商店标识
+func redisKeyPrefix(src Source, gameID models.GameID) string {
+	if isPrimarySource(src, gameID) {
+		return "game:"
+	}
+	return "game:" + src.Name() + "-"
+}
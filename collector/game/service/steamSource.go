@@ -0,0 +1,730 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+	"github.com/GoFurry/gofurry-game-collector/roof/env"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/bytedance/sonic"
+	"github.com/tidwall/gjson"
+)
+
+// Steam 数据源的商店标识
+const STORE_STEAM = "steam"
+
+// steamSource Steam 商店适配器
+type steamSource struct{}
+
+func (s steamSource) Name() string { return STORE_STEAM }
+
+func (s steamSource) Supports(gameID models.GameID) bool { return gameID.Appid != 0 }
+
+// 构建请求头, 每次请求独立一份避免并发写
+func newHeaders(acceptLanguage string) map[string]string {
+	return map[string]string{
+		"User-Agent":      common.USER_AGENT,
+		"Accept-Language": acceptLanguage,
+	}
+}
+
+// FetchDetails 按配置的语言采集 appdetails
+func (s steamSource) FetchDetails(gameID models.GameID) (map[string]models.StoreDetail, common.GFError) {
+	appidStr := util.Int642String(gameID.Appid)
+	infoRes := make(map[string]models.StoreDetail)
+
+	// 请求地址
+	url := `https://store.steampowered.com/api/appdetails`
+
+	for _, v := range getLanguageList() {
+		// 语言较多时每次请求都需要令牌
+		if err := steamStoreLimiter.Wait(context.Background()); err != nil {
+			return infoRes, common.NewServiceError("获取限流令牌失败: " + err.Error())
+		}
+
+		// 设置采集的国区和语言
+		paramsMap := map[string]string{
+			"appids": appidStr,
+			"cc":     v.CC,
+			"l":      v.Code,
+		}
+		if v.CC == "" {
+			paramsMap["cc"] = "US"
+		}
+		acceptLanguage := v.AcceptLanguage
+		if acceptLanguage == "" {
+			acceptLanguage = common.ACCEPT_LANGUAGE_EN
+		}
+
+		// 请求 SteamAPI
+		respDataStr, httpErr := util.GetByHttpWithParams(url, newHeaders(acceptLanguage), paramsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+		if httpErr != nil {
+			return infoRes, common.NewServiceError(httpErr.Error())
+		}
+
+		// 判断是否成功, 锁区游戏国区请求会返回错误
+		if !gjson.Get(respDataStr, appidStr+".success").Bool() {
+			continue
+		}
+
+		detail := parseSteamAppDetail(gjson.Get(respDataStr, appidStr+".data").Raw)
+
+		// 用户标签, 失败时不影响详情
+		tags, gfErr := fetchSteamUserTags(appidStr, v)
+		if gfErr != nil {
+			log.Warn("采集用户标签失败, game_id=", gameID.ID, " lang=", v.Lang, " err:", gfErr.GetMsg())
+		}
+		detail.Tags = tags
+
+		infoRes[v.Lang] = detail
+	}
+
+	return infoRes, nil
+}
+
+// 解析 appdetails 的 data 部分
+func parseSteamAppDetail(dataStr string) (detail models.StoreDetail) {
+	detail.Name = gjson.Get(dataStr, "name").String()                                      // 游戏名称
+	detail.IsFree = gjson.Get(dataStr, "is_free").Bool()                                   // 是否免费
+	detail.SupportedLanguages = gjson.Get(dataStr, "supported_languages").String()         // 支持的语言
+	detail.HeaderImage = gjson.Get(dataStr, "header_image").String()                       // 封面图
+	detail.ShortDescription = gjson.Get(dataStr, "short_description").String()             // 概述
+	detail.RequiredAge = gjson.Get(dataStr, "ratings.steam_germany.required_age").String() // 年龄限制
+	detail.Website = gjson.Get(dataStr, "website").String()                                // 游戏官网
+	detail.ContentDescriptors = gjson.Get(dataStr, "content_descriptors.notes").String()   // 内容描述
+	detail.DetailedDescription = gjson.Get(dataStr, "detailed_description").String()       // 详情描述
+	detail.AboutTheGame = gjson.Get(dataStr, "about_the_game").String()                    // 关于游戏
+
+	unmarshalSteamField(dataStr, "release_date", &detail.ReleaseDate)       // 发行日期
+	unmarshalSteamField(dataStr, "platforms", &detail.Platforms)            // 支持平台
+	unmarshalSteamField(dataStr, "developers", &detail.Developers)          // 开发商
+	unmarshalSteamField(dataStr, "publishers", &detail.Publishers)          // 发行商
+	unmarshalSteamField(dataStr, "support_info", &detail.SupportInfo)       // 开发商联系方式
+	unmarshalSteamField(dataStr, "screenshots", &detail.Screenshots)        // 游戏图片
+	unmarshalSteamField(dataStr, "movies", &detail.Movies)                  // 游戏视频
+	unmarshalSteamField(dataStr, "pc_requirements", &detail.PcRequirements) // 配置需求
+
+	unmarshalSteamField(dataStr, "dlc", &detail.DLC)             // DLC 和原声音乐
+	detail.FullGame = gjson.Get(dataStr, "fullgame.appid").Int() // 本体
+	gjson.Get(dataStr, "demos.#.appid").ForEach(func(_, v gjson.Result) bool {
+		detail.Demos = append(detail.Demos, v.Int()) // 试玩版
+		return true
+	})
+	detail.Packages = parseSteamPackages(dataStr)                        // 礼包
+	detail.Genres = parseSteamTags(gjson.Get(dataStr, "genres"))         // 类型
+	detail.Categories = parseSteamTags(gjson.Get(dataStr, "categories")) // 功能
+
+	if detail.Screenshots == nil {
+		detail.Screenshots = []models.SteamAppScreenshot{}
+	}
+	if detail.Movies == nil {
+		detail.Movies = []models.SteamAppMovie{}
+	}
+	return
+}
+
+// 解析 packages 和 package_groups 中的礼包, 去重后保持商店页顺序
+func parseSteamPackages(dataStr string) (packages []int64) {
+	seen := make(map[int64]bool)
+	add := func(id int64) {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			packages = append(packages, id)
+		}
+	}
+	gjson.Get(dataStr, "package_groups").ForEach(func(_, group gjson.Result) bool {
+		group.Get("subs").ForEach(func(_, sub gjson.Result) bool {
+			add(sub.Get("packageid").Int())
+			return true
+		})
+		return true
+	})
+	gjson.Get(dataStr, "packages").ForEach(func(_, v gjson.Result) bool {
+		add(v.Int())
+		return true
+	})
+	return
+}
+
+// 解析 genres 和 categories, genres 的 id 为字符串
+func parseSteamTags(res gjson.Result) (tags []models.StoreTag) {
+	res.ForEach(func(_, v gjson.Result) bool {
+		if id := v.Get("id").Int(); id != 0 {
+			tags = append(tags, models.StoreTag{ID: id, Name: v.Get("description").String()})
+		}
+		return true
+	})
+	return
+}
+
+// 商店页中的用户标签, InitAppTagModal( appid, [标签列表], ...)
+var steamUserTagPattern = regexp.MustCompile(`InitAppTagModal\(\s*\d+,\s*(\[.*?\])\s*,`)
+
+// 从商店页采集热门用户标签, 需要带上年龄确认的 cookie
+func fetchSteamUserTags(appidStr string, lang env.LanguageConfig) ([]models.StoreTag, common.GFError) {
+	if err := steamStoreLimiter.Wait(context.Background()); err != nil {
+		return nil, common.NewServiceError("获取限流令牌失败: " + err.Error())
+	}
+
+	headers := newHeaders(lang.AcceptLanguage)
+	if lang.AcceptLanguage == "" {
+		headers["Accept-Language"] = common.ACCEPT_LANGUAGE_EN
+	}
+	headers["Cookie"] = "birthtime=0; lastagecheckage=1-0-1990; wants_mature_content=1; mature_content=1"
+	paramsMap := map[string]string{"l": lang.Code}
+	if lang.CC != "" {
+		paramsMap["cc"] = lang.CC
+	}
+
+	respDataStr, httpErr := util.GetByHttpWithParams(`https://store.steampowered.com/app/`+appidStr+`/`, headers, paramsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+	if httpErr != nil {
+		return nil, common.NewServiceError(httpErr.Error())
+	}
+	match := steamUserTagPattern.FindStringSubmatch(respDataStr)
+	if match == nil {
+		return nil, common.NewServiceError("商店页未找到用户标签")
+	}
+
+	var tags []models.StoreTag
+	gjson.Parse(match[1]).ForEach(func(_, v gjson.Result) bool {
+		if id := v.Get("tagid").Int(); id != 0 {
+			tags = append(tags, models.StoreTag{ID: id, Name: v.Get("name").String(), Votes: v.Get("count").Int()})
+		}
+		return true
+	})
+	return tags, nil
+}
+
+// 将 data 中的字段转换为对应结构, 字段缺失时保持零值
+func unmarshalSteamField(dataStr string, path string, v any) {
+	tempDataStr := gjson.Get(dataStr, path).Raw
+	// Steam 对空对象会返回 [], 此时保持零值
+	if tempDataStr == "" || tempDataStr == "[]" {
+		return
+	}
+	if jsonErr := sonic.Unmarshal([]byte(tempDataStr), v); jsonErr != nil {
+		log.Error(path+" json转换错误.", jsonErr)
+	}
+}
+
+// FetchPrices 按采集国区获取价格
+func (s steamSource) FetchPrices(gameID models.GameID) (map[string]models.SteamAppPrice, common.GFError) {
+	appidStr := util.Int642String(gameID.Appid)
+	priceRes := make(map[string]models.SteamAppPrice)
+
+	// 请求地址
+	url := `https://store.steampowered.com/api/appdetails`
+
+	for _, region := range getRegionList() {
+		// 每个国区一次请求, 各取一个令牌
+		if err := steamStoreLimiter.Wait(context.Background()); err != nil {
+			return priceRes, common.NewServiceError("获取限流令牌失败: " + err.Error())
+		}
+
+		// 只请求价格部分
+		paramsMap := map[string]string{
+			"appids":  appidStr,
+			"cc":      region.CC,
+			"filters": "price_overview",
+		}
+
+		// 请求 SteamAPI
+		respDataStr, httpErr := util.GetByHttpWithParams(url, newHeaders(region.AcceptLanguage), paramsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+		if httpErr != nil {
+			return priceRes, common.NewServiceError(httpErr.Error())
+		}
+
+		// 判断是否成功, 锁区游戏国区请求会返回错误
+		if !gjson.Get(respDataStr, appidStr+".success").Bool() {
+			continue
+		}
+
+		// 免费或未发售的游戏没有价格
+		var newPrice models.SteamAppPrice
+		unmarshalSteamField(gjson.Get(respDataStr, appidStr+".data").Raw, "price_overview", &newPrice)
+		priceRes[region.CC] = newPrice
+	}
+
+	return priceRes, nil
+}
+
+// FetchNews 采集更新公告, 英文来自 SteamAPI, 中文来自商店接口
+func (s steamSource) FetchNews(gameID models.GameID, cnt int) (map[string][]models.SteamAppNews, common.GFError) {
+	newsRes, _, gfErr := s.FetchNewsPage(gameID, "", cnt)
+	return newsRes, gfErr
+}
+
+// Steam 公告翻页游标 <enddate>|<gidevent>, 分别为 GetNewsForApp 的截止时间和商店接口的锚点活动id
+// 某一侧没有更多公告时记为 end
+const steamNewsCursorEnd = "end"
+
+// FetchNewsPage 从 cursor 开始向前采集 cnt 篇更新公告, 返回下一页游标, 没有更多公告时返回空游标
+func (s steamSource) FetchNewsPage(gameID models.GameID, cursor string, cnt int) (map[string][]models.SteamAppNews, string, common.GFError) {
+	if err := steamStoreLimiter.Wait(context.Background()); err != nil {
+		return nil, "", common.NewServiceError("获取限流令牌失败: " + err.Error())
+	}
+
+	appidStr := util.Int642String(gameID.Appid)
+	cntStr := util.Int2String(cnt)
+	newsRes := make(map[string][]models.SteamAppNews)
+	endDate, gidEvent, _ := strings.Cut(cursor, "|")
+	nextEndDate, nextGidEvent := steamNewsCursorEnd, steamNewsCursorEnd
+	loc, _ := time.LoadLocation("Asia/Shanghai") // 中国 CST（UTC+8）
+
+	// 请求地址
+	apiUrl := `https://api.steampowered.com/ISteamNews/GetNewsForApp/v2`             // steamAPI 仅返回英文 请求速度慢
+	storeUrl := `https://store.steampowered.com/events/ajaxgetadjacentpartnerevents` // 商店API 返回语言可选 请求速度快
+
+	// SteamAPI 请求英文数据
+	if endDate != steamNewsCursorEnd {
+		apiParamsMap := map[string]string{
+			"appid": appidStr,
+			"count": cntStr,
+		}
+		if endDate != "" {
+			apiParamsMap["enddate"] = endDate
+		}
+		respDataStr, httpErr := util.GetByHttpWithParams(apiUrl, newHeaders(common.ACCEPT_LANGUAGE_CN), apiParamsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+		if httpErr != nil {
+			return newsRes, "", common.NewServiceError("api.steampowered.com/ISteamNews/GetNewsForApp 请求失败: " + httpErr.Error())
+		}
+
+		// 解析更新公告
+		var oldestDate int64
+		newsItems := gjson.Get(respDataStr, "appnews.newsitems").Array()
+		for _, item := range newsItems {
+			// 存储结构
+			nowNews := models.SteamAppNews{}
+
+			nowNews.Gid = item.Get("gid").String()                        // 公告id
+			nowNews.Title = item.Get("title").String()                    // 标题
+			nowNews.Author = item.Get("author").String()                  // 作者
+			nowNews.URL = item.Get("url").String()                        // URL
+			nowNews.Count = gjson.Get(respDataStr, "appnews.count").Int() // 更新公告数
+			// 日期
+			oldestDate = item.Get("date").Int()
+			nowNews.Date = cm.LocalTime(time.Unix(oldestDate, 0).In(loc))
+			// 内容
+			nowNews.Contents = util.ParseBBCode(item.Get("contents").String())
+
+			// 存储结果
+			newsRes["en"] = append(newsRes["en"], nowNews)
+		}
+
+		// enddate 包含当天公告, 截止时间不再前进时结束
+		if len(newsItems) >= cnt && util.Int642String(oldestDate) != endDate {
+			nextEndDate = util.Int642String(oldestDate)
+		}
+	}
+
+	// SteamStoreAPI 请求中文数据
+	if gidEvent != steamNewsCursorEnd {
+		storeParamsMap := map[string]string{
+			"appid":        appidStr,
+			"count_before": "1",
+			"count_after":  cntStr,
+			"lang_list":    "6_0",
+		}
+		if gidEvent != "" {
+			storeParamsMap["count_before"] = "0"
+			storeParamsMap["gidevent"] = gidEvent
+		}
+		respDataStr, httpErr := util.GetByHttpWithParams(storeUrl, newHeaders(common.ACCEPT_LANGUAGE_CN), storeParamsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+		if httpErr != nil {
+			return newsRes, "", common.NewServiceError("store.steampowered.com/events/ajaxgetadjacentpartnerevents 请求失败: " + httpErr.Error())
+		}
+
+		// 按公告id匹配英文公告, 作者、地址和日期取英文公告, 匹配不到时只记录中文
+		enNewsMap := make(map[string]models.SteamAppNews)
+		for _, v := range newsRes["en"] {
+			enNewsMap[v.Gid] = v
+		}
+		events := gjson.Get(respDataStr, "events").Array()
+		for _, event := range events {
+			// 跳过作为锚点的上一页最后一篇活动
+			announcementGid := event.Get("announcement_body.gid").String()
+			if event.Get("gid").String() == gidEvent || announcementGid == "" {
+				continue
+			}
+
+			// 存储结构
+			nowNews := models.SteamAppNews{}
+
+			nowNews.Gid = announcementGid                                                     // 公告id
+			nowNews.Title = event.Get("announcement_body.headline").String()                  // 标题
+			nowNews.Contents = util.ParseBBCode(event.Get("announcement_body.body").String()) // 内容
+			if enNews, ok := enNewsMap[announcementGid]; ok {
+				nowNews.Author = enNews.Author // 作者
+				nowNews.URL = enNews.URL       // URL
+				nowNews.Date = enNews.Date     // 日期
+				nowNews.Count = enNews.Count   // 更新公告数
+			} else {
+				nowNews.URL = "https://store.steampowered.com/news/app/" + appidStr + "/view/" + event.Get("gid").String()
+				nowNews.Date = cm.LocalTime(time.Unix(event.Get("announcement_body.posttime").Int(), 0).In(loc))
+			}
+
+			// 存储结果
+			newsRes["zh"] = append(newsRes["zh"], nowNews)
+		}
+
+		// 以最后一篇活动为下一页锚点
+		if len(events) > 0 {
+			if lastGid := events[len(events)-1].Get("gid").String(); lastGid != gidEvent {
+				nextGidEvent = lastGid
+			}
+		}
+	}
+
+	if nextEndDate == steamNewsCursorEnd && nextGidEvent == steamNewsCursorEnd {
+		return newsRes, "", nil
+	}
+	return newsRes, nextEndDate + "|" + nextGidEvent, nil
+}
+
+// FetchRelatedApp 采集关联应用的基本信息, 使用第一个采集语言
+func (s steamSource) FetchRelatedApp(appid int64) (models.RelatedApp, common.GFError) {
+	var app models.RelatedApp
+	if err := steamStoreLimiter.Wait(context.Background()); err != nil {
+		return app, common.NewServiceError("获取限流令牌失败: " + err.Error())
+	}
+
+	appidStr := util.Int642String(appid)
+	lang := getLanguageList()[0]
+	paramsMap := map[string]string{
+		"appids": appidStr,
+		"cc":     lang.CC,
+		"l":      lang.Code,
+	}
+	if lang.CC == "" {
+		paramsMap["cc"] = "US"
+	}
+	acceptLanguage := lang.AcceptLanguage
+	if acceptLanguage == "" {
+		acceptLanguage = common.ACCEPT_LANGUAGE_EN
+	}
+
+	respDataStr, httpErr := util.GetByHttpWithParams(`https://store.steampowered.com/api/appdetails`, newHeaders(acceptLanguage), paramsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+	if httpErr != nil {
+		return app, common.NewServiceError(httpErr.Error())
+	}
+	if !gjson.Get(respDataStr, appidStr+".success").Bool() {
+		return app, common.NewServiceError("appdetails 请求失败: " + appidStr)
+	}
+
+	dataStr := gjson.Get(respDataStr, appidStr+".data").Raw
+	app.Name = gjson.Get(dataStr, "name").String()
+	app.Type = gjson.Get(dataStr, "type").String()
+	app.IsFree = gjson.Get(dataStr, "is_free").Bool()
+	app.HeaderImage = gjson.Get(dataStr, "header_image").String()
+	unmarshalSteamField(dataStr, "release_date", &app.ReleaseDate)
+	return app, nil
+}
+
+// FetchRelatedPrices 按采集国区批量获取关联应用价格, 免费或未发售的应用没有价格
+func (s steamSource) FetchRelatedPrices(appids []int64) (map[int64]map[string]models.SteamAppPrice, common.GFError) {
+	priceRes, gfErr := fetchSteamPriceBatch(appids, steamPriceBatchSize)
+	for appid, prices := range priceRes {
+		for cc, price := range prices {
+			if price.Currency == "" && price.FinalFormatted == "" {
+				delete(prices, cc)
+			}
+		}
+		if len(prices) == 0 {
+			delete(priceRes, appid)
+		}
+	}
+	return priceRes, gfErr
+}
+
+// FetchPriceBatch 批量获取游戏各国区价格, key 为游戏表id和国区代码
+func (s steamSource) FetchPriceBatch(gameList []models.GameID) (map[int64]map[string]models.SteamAppPrice, common.GFError) {
+	gameIDs := make(map[int64]int64)
+	appids := make([]int64, 0, len(gameList))
+	for _, v := range gameList {
+		gameIDs[v.Appid] = v.ID
+		appids = append(appids, v.Appid)
+	}
+
+	batchSize := env.GetServerConfig().Collector.Game.PriceBatch
+	if batchSize <= 0 {
+		batchSize = steamPriceBatchSize
+	}
+	appRes, gfErr := fetchSteamPriceBatch(appids, batchSize)
+	priceRes := make(map[int64]map[string]models.SteamAppPrice)
+	for appid, prices := range appRes {
+		priceRes[gameIDs[appid]] = prices
+	}
+	return priceRes, gfErr
+}
+
+// 只请求价格时每次请求的 appid 数
+const steamPriceBatchSize = 100
+
+// 使用 filters=price_overview 批量请求 appdetails, 一次请求包含多个 appid
+// 请求成功但没有价格的应用同样返回, 价格为空, 与 FetchPrices 一致
+func fetchSteamPriceBatch(appids []int64, batchSize int) (map[int64]map[string]models.SteamAppPrice, common.GFError) {
+	priceRes := make(map[int64]map[string]models.SteamAppPrice)
+
+	// 请求地址
+	url := `https://store.steampowered.com/api/appdetails`
+
+	for i := 0; i < len(appids); i += batchSize {
+		batch := appids[i:min(i+batchSize, len(appids))]
+		appidStrs := make([]string, len(batch))
+		for j, v := range batch {
+			appidStrs[j] = util.Int642String(v)
+		}
+
+		for _, region := range getRegionList() {
+			if err := steamStoreLimiter.Wait(context.Background()); err != nil {
+				return priceRes, common.NewServiceError("获取限流令牌失败: " + err.Error())
+			}
+			paramsMap := map[string]string{
+				"appids":  strings.Join(appidStrs, ","),
+				"cc":      region.CC,
+				"filters": "price_overview",
+			}
+
+			respDataStr, httpErr := util.GetByHttpWithParams(url, newHeaders(region.AcceptLanguage), paramsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+			if httpErr != nil {
+				return priceRes, common.NewServiceError(httpErr.Error())
+			}
+
+			for j, appidStr := range appidStrs {
+				// 锁区游戏国区请求会返回错误
+				if !gjson.Get(respDataStr, appidStr+".success").Bool() {
+					continue
+				}
+				var price models.SteamAppPrice
+				unmarshalSteamField(gjson.Get(respDataStr, appidStr+".data").Raw, "price_overview", &price)
+				if priceRes[batch[j]] == nil {
+					priceRes[batch[j]] = make(map[string]models.SteamAppPrice)
+				}
+				priceRes[batch[j]][region.CC] = price
+			}
+		}
+	}
+
+	return priceRes, nil
+}
+
+// FetchPackages 按采集国区批量获取礼包, 名称使用第一个国区的结果
+func (s steamSource) FetchPackages(packageIDs []int64) (map[int64]models.StorePackage, common.GFError) {
+	packageRes := make(map[int64]models.StorePackage)
+
+	// 请求地址
+	url := `https://store.steampowered.com/api/packagedetails`
+
+	const batchSize = 50
+	for i := 0; i < len(packageIDs); i += batchSize {
+		batch := packageIDs[i:min(i+batchSize, len(packageIDs))]
+		idStrs := make([]string, len(batch))
+		for j, v := range batch {
+			idStrs[j] = util.Int642String(v)
+		}
+
+		for _, region := range getRegionList() {
+			if err := steamStoreLimiter.Wait(context.Background()); err != nil {
+				return packageRes, common.NewServiceError("获取限流令牌失败: " + err.Error())
+			}
+			paramsMap := map[string]string{
+				"packageids": strings.Join(idStrs, ","),
+				"cc":         region.CC,
+			}
+
+			respDataStr, httpErr := util.GetByHttpWithParams(url, newHeaders(region.AcceptLanguage), paramsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+			if httpErr != nil {
+				return packageRes, common.NewServiceError(httpErr.Error())
+			}
+
+			for j, idStr := range idStrs {
+				// 锁区或已下架的礼包返回失败
+				if !gjson.Get(respDataStr, idStr+".success").Bool() {
+					continue
+				}
+				dataStr := gjson.Get(respDataStr, idStr+".data").Raw
+				pkg, exist := packageRes[batch[j]]
+				if !exist {
+					pkg.Name = gjson.Get(dataStr, "name").String()
+					unmarshalSteamField(dataStr, "apps", &pkg.Apps)
+					pkg.Prices = make(map[string]models.PackagePrice)
+				}
+
+				// 免费礼包没有价格
+				var price models.PackagePrice
+				unmarshalSteamField(dataStr, "price", &price)
+				if price.Currency != "" {
+					pkg.Prices[region.CC] = price
+				}
+				packageRes[batch[j]] = pkg
+			}
+		}
+	}
+
+	return packageRes, nil
+}
+
+// 商店搜索每页数量
+const steamSearchPageSize = 50
+
+// SearchGames 通过商店搜索结果接口按标签或关键词搜索游戏, 只保留单个游戏, 跳过礼包和捆绑包
+func (s steamSource) SearchGames(tagID int64, keyword string, maxPages int) ([]models.SearchResult, common.GFError) {
+	conf := env.GetServerConfig().Collector.Game.Discovery
+	var searchRes []models.SearchResult
+
+	// 请求地址
+	url := `https://store.steampowered.com/search/results/`
+
+	for page := 0; page < maxPages; page++ {
+		if err := steamStoreLimiter.Wait(context.Background()); err != nil {
+			return searchRes, common.NewServiceError("获取限流令牌失败: " + err.Error())
+		}
+		paramsMap := map[string]string{
+			"infinite":  "1",
+			"json":      "1",
+			"category1": "998", // 只搜索游戏
+			"start":     util.Int2String(page * steamSearchPageSize),
+			"count":     util.Int2String(steamSearchPageSize),
+			"l":         "english",
+		}
+		if tagID != 0 {
+			paramsMap["tags"] = util.Int642String(tagID)
+		}
+		if keyword != "" {
+			paramsMap["term"] = keyword
+		}
+		if conf.CC != "" {
+			paramsMap["cc"] = conf.CC
+		}
+
+		respDataStr, httpErr := util.GetByHttpWithParams(url, newHeaders(common.ACCEPT_LANGUAGE_EN), paramsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+		if httpErr != nil {
+			return searchRes, common.NewServiceError(httpErr.Error())
+		}
+		if gjson.Get(respDataStr, "success").Int() != 1 {
+			return searchRes, common.NewServiceError("商店搜索失败")
+		}
+
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(gjson.Get(respDataStr, "results_html").String()))
+		if err != nil {
+			return searchRes, common.NewServiceError("解析搜索结果失败: " + err.Error())
+		}
+		rows := doc.Find("a.search_result_row")
+		rows.Each(func(i int, sel *goquery.Selection) {
+			// 捆绑包的 appid 为逗号分隔的列表, 礼包没有 appid
+			appid, parseErr := strconv.ParseInt(sel.AttrOr("data-ds-appid", ""), 10, 64)
+			if parseErr != nil || appid == 0 {
+				return
+			}
+			searchRes = append(searchRes, models.SearchResult{
+				Appid:         appid,
+				Name:          strings.TrimSpace(sel.Find(".title").First().Text()),
+				ReleaseDate:   strings.TrimSpace(sel.Find(".search_released").First().Text()),
+				HeaderImage:   sel.Find(".search_capsule img").AttrOr("src", ""),
+				Price:         strings.TrimSpace(sel.Find(".discount_final_price").First().Text()),
+				ReviewSummary: strings.ReplaceAll(sel.Find(".search_review_summary").AttrOr("data-tooltip-html", ""), "<br>", " "),
+			})
+		})
+
+		// 最后一页
+		if rows.Length() < steamSearchPageSize || (page+1)*steamSearchPageSize >= int(gjson.Get(respDataStr, "total_count").Int()) {
+			break
+		}
+	}
+
+	return searchRes, nil
+}
+
+// 近期评测统计的天数, 与商店页面的最近评测一致
+const steamRecentReviewDays = 30
+
+// FetchReviews 按采集语言获取评测汇总, 全部评测和近期评测各请求一次
+func (s steamSource) FetchReviews(gameID models.GameID) (map[string]models.ReviewSummary, common.GFError) {
+	appidStr := util.Int642String(gameID.Appid)
+	reviewRes := make(map[string]models.ReviewSummary)
+
+	// 请求地址
+	url := `https://store.steampowered.com/appreviews/` + appidStr
+
+	now := time.Now()
+	for _, v := range getLanguageList() {
+		var summary models.ReviewSummary
+
+		// 全部评测
+		paramsMap := map[string]string{
+			"json":          "1",
+			"num_per_page":  "0",
+			"language":      v.Code,
+			"purchase_type": "all",
+			"filter":        "all",
+		}
+		if gfErr := fetchSteamReviewSummary(url, paramsMap, v.AcceptLanguage, &summary.All); gfErr != nil {
+			return reviewRes, gfErr
+		}
+
+		// 近期评测, 按发布时间范围统计
+		paramsMap["start_date"] = util.Int642String(now.AddDate(0, 0, -steamRecentReviewDays).Unix())
+		paramsMap["end_date"] = util.Int642String(now.Unix())
+		paramsMap["date_range_type"] = "include"
+		if gfErr := fetchSteamReviewSummary(url, paramsMap, v.AcceptLanguage, &summary.Recent); gfErr != nil {
+			return reviewRes, gfErr
+		}
+
+		reviewRes[v.Lang] = summary
+	}
+
+	return reviewRes, nil
+}
+
+// 请求 appreviews 并解析 query_summary
+func fetchSteamReviewSummary(url string, paramsMap map[string]string, acceptLanguage string, summary *models.SteamReviewSummary) common.GFError {
+	if err := steamStoreLimiter.Wait(context.Background()); err != nil {
+		return common.NewServiceError("获取限流令牌失败: " + err.Error())
+	}
+	if acceptLanguage == "" {
+		acceptLanguage = common.ACCEPT_LANGUAGE_EN
+	}
+
+	respDataStr, httpErr := util.GetByHttpWithParams(url, newHeaders(acceptLanguage), paramsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+	if httpErr != nil {
+		return common.NewServiceError(httpErr.Error())
+	}
+	if gjson.Get(respDataStr, "success").Int() != 1 {
+		return common.NewServiceError("appreviews 请求失败: " + paramsMap["language"])
+	}
+	unmarshalSteamField(respDataStr, "query_summary", summary)
+	return nil
+}
+
+// FetchPlayerCount 采集当前在线人数
+func (s steamSource) FetchPlayerCount(gameID models.GameID) (int64, common.GFError) {
+	if err := steamAPILimiter.Wait(context.Background()); err != nil {
+		return 0, common.NewServiceError("获取限流令牌失败: " + err.Error())
+	}
+
+	appidStr := util.Int642String(gameID.Appid)
+
+	// 请求地址
+	url := `https://api.steampowered.com/ISteamUserStats/GetNumberOfCurrentPlayers/v1/?`
+
+	// 设置参数
+	paramsMap := map[string]string{
+		"appid": appidStr,
+	}
+
+	// 请求 SteamAPI
+	respDataStr, httpErr := util.GetByHttpWithParams(url, newHeaders(common.ACCEPT_LANGUAGE_CN), paramsMap, 10*time.Second, &env.GetServerConfig().Collector.Proxy)
+	if httpErr != nil {
+		return 0, common.NewServiceError(httpErr.Error())
+	}
+
+	return gjson.Get(respDataStr, "response.player_count").Int(), nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"time"
+
+	"github.com/GoFurry/gofurry-game-collector/collector/game/dao"
+	"github.com/GoFurry/gofurry-game-collector/collector/game/models"
+	"github.com/GoFurry/gofurry-game-collector/common/log"
+	cm "github.com/GoFurry/gofurry-game-collector/common/models"
+	"github.com/GoFurry/gofurry-game-collector/common/util"
+)
+
+// 标签名称列表
+func tagNames(tags []models.StoreTag) []string {
+	names := make([]string, 0, len(tags))
+	for _, v := range tags {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+// 详情中对应类别的标签
+func detailTags(detail models.StoreDetail, kind string) []models.StoreTag {
+	switch kind {
+	case models.TAG_KIND_GENRE:
+		return detail.Genres
+	case models.TAG_KIND_CATEGORY:
+		return detail.Categories
+	case models.TAG_KIND_TAG:
+		return detail.Tags
+	}
+	return nil
+}
+
+// 保存游戏的类型、功能和用户标签, 各语言的名称分别保存
+// 所有语言都没有采集到的类别跳过, 避免采集失败时清空已有关联
+func saveGameTags(src Source, gameID models.GameID, infoRes map[string]models.StoreDetail) {
+	now := cm.LocalTime(time.Now())
+	for _, kind := range []string{models.TAG_KIND_GENRE, models.TAG_KIND_CATEGORY, models.TAG_KIND_TAG} {
+		// 按第一次出现的顺序合并各语言的标签
+		var order []int64
+		names := make(map[int64]map[string]string)
+		votes := make(map[int64]int64)
+		for _, lang := range util.SortedKeys(infoRes) {
+			for _, v := range detailTags(infoRes[lang], kind) {
+				if _, ok := names[v.ID]; !ok {
+					order = append(order, v.ID)
+					names[v.ID] = make(map[string]string)
+				}
+				names[v.ID][lang] = v.Name
+				votes[v.ID] = max(votes[v.ID], v.Votes)
+			}
+		}
+		if len(order) == 0 {
+			continue
+		}
+
+		links := make([]models.GfgGameTagLink, 0, len(order))
+		for i, storeTagID := range order {
+			tagID, err := dao.GetGameTagDao().SaveTag(kind, src.Name(), storeTagID)
+			if err != nil {
+				log.Error("SaveTag error: ", err.GetMsg())
+				return
+			}
+			for lang, name := range names[storeTagID] {
+				if err = dao.GetGameTagDao().SaveTagName(tagID, lang, name); err != nil {
+					log.Error("SaveTagName error: ", err.GetMsg())
+				}
+			}
+			links = append(links, models.GfgGameTagLink{
+				ID:         util.GenerateId(),
+				GameID:     gameID.ID,
+				TagID:      tagID,
+				Sort:       int64(i),
+				Votes:      votes[storeTagID],
+				UpdateTime: now,
+			})
+		}
+		if err := dao.GetGameTagDao().ReplaceGameTags(gameID.ID, kind, src.Name(), links); err != nil {
+			log.Error("ReplaceGameTags error: ", err.GetMsg())
+		}
+	}
+}
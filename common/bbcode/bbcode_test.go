@@ -0,0 +1,110 @@
+package bbcode
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// go test ./common/bbcode -update 重新生成 golden 文件
+var update = flag.Bool("update", false, "重新生成 testdata 中的 golden 文件")
+
+// testdata 中的 *.bbcode 为 Steam 公告原文, 同名 .html/.txt/.md 为期望输出
+func TestGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.bbcode"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("testdata 中没有 .bbcode 文件")
+	}
+	formats := []struct {
+		ext    string
+		render func(string) string
+	}{
+		{".html", HTML},
+		{".txt", Text},
+		{".md", Markdown},
+	}
+	for _, input := range inputs {
+		src, err := os.ReadFile(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		base := strings.TrimSuffix(input, ".bbcode")
+		for _, f := range formats {
+			t.Run(filepath.Base(base)+f.ext, func(t *testing.T) {
+				got := f.render(string(src))
+				golden := base + f.ext
+				if *update {
+					if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != string(want) {
+					t.Errorf("输出与 %s 不一致\n--- got ---\n%s\n--- want ---\n%s", golden, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestUnsafeScheme(t *testing.T) {
+	cases := []string{
+		`[url=javascript:alert(1)]x[/url]`,
+		`[url="JAVASCRIPT:alert(1)"]x[/url]`,
+		`[url] javascript:alert(1)[/url]`,
+		`[url=data:text/html;base64,PHNjcmlwdD4=]x[/url]`,
+		`[img]javascript:alert(1)[/img]`,
+		`[img]data:image/svg+xml;base64,PHN2Zz4=[/img]`,
+		`[img=vbscript:msgbox(1)][/img]`,
+		`[dynamiclink href="javascript:alert(1)"][/dynamiclink]`,
+		`[video mp4="javascript:alert(1)" poster="javascript:alert(2)"][/video]`,
+	}
+	// 不安全的地址不能出现在链接和图片地址中, 作为普通文本保留即可
+	unsafe := []string{"javascript:", "data:", "vbscript:"}
+	for _, c := range cases {
+		html := strings.ToLower(HTML(c))
+		text := strings.ToLower(Text(c))
+		markdown := strings.ToLower(Markdown(c))
+		for _, scheme := range unsafe {
+			for _, prefix := range []string{`href="`, `src="`, `poster="`} {
+				if strings.Contains(html, prefix+scheme) {
+					t.Errorf("HTML(%q) = %q", c, html)
+				}
+			}
+			if strings.Contains(text, "("+scheme) {
+				t.Errorf("Text(%q) = %q", c, text)
+			}
+			if strings.Contains(markdown, "]("+scheme) || strings.Contains(markdown, "<"+scheme) {
+				t.Errorf("Markdown(%q) = %q", c, markdown)
+			}
+		}
+	}
+}
+
+func TestSafeScheme(t *testing.T) {
+	cases := map[string]string{
+		`[url=https://example.com]x[/url]`:       `<a href="https://example.com"`,
+		`[url=HTTP://example.com]x[/url]`:        `<a href="HTTP://example.com"`,
+		`[url=steam://store/1599600]x[/url]`:     `<a href="steam://store/1599600"`,
+		`[img]https://example.com/a.png[/img]`:   `<img src="https://example.com/a.png"`,
+		`[url=https://example.com/a_(b)]x[/url]`: `<a href="https://example.com/a_(b)"`,
+	}
+	for input, want := range cases {
+		if got := HTML(input); !strings.Contains(got, want) {
+			t.Errorf("HTML(%q) = %q, want contains %q", input, got, want)
+		}
+	}
+	// Markdown 中转义括号, 避免提前结束链接
+	if got := Markdown(`[url=https://example.com/a_(b)]x[/url]`); got != `[x](https://example.com/a_%28b%29)` {
+		t.Errorf("Markdown = %q", got)
+	}
+}
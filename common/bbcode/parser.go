@@ -0,0 +1,134 @@
+package bbcode
+
+/*
+ * @Desc: BBCode 语法分析
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+import "strings"
+
+// NodeType 节点类型
+type NodeType int
+
+const (
+	DocumentNode NodeType = iota // 根节点
+	TextNode                     // 文本节点
+	TagNode                      // 标签节点
+)
+
+// Node 语法树节点
+type Node struct {
+	Type     NodeType
+	Tag      *Tag              // 标签定义, 仅标签节点
+	Name     string            // 标签名
+	Value    string            // [name=value] 形式的参数
+	Attrs    map[string]string // [name key="value"] 形式的属性
+	Text     string            // 文本内容, 仅文本节点和 Raw 标签
+	Parent   *Node
+	Children []*Node
+}
+
+// Attr 获取属性, 不存在时返回空
+func (n *Node) Attr(key string) string { return n.Attrs[key] }
+
+// PlainText 获取节点下的全部文本
+func (n *Node) PlainText() string {
+	if n.Type == TextNode {
+		return n.Text
+	}
+	var buf strings.Builder
+	buf.WriteString(n.Text)
+	for _, child := range n.Children {
+		buf.WriteString(child.PlainText())
+	}
+	return buf.String()
+}
+
+func (n *Node) appendChild(child *Node) {
+	child.Parent = n
+	// 合并相邻文本
+	if child.Type == TextNode && len(n.Children) > 0 {
+		if last := n.Children[len(n.Children)-1]; last.Type == TextNode {
+			last.Text += child.Text
+			return
+		}
+	}
+	n.Children = append(n.Children, child)
+}
+
+// Parse 使用默认注册表解析 BBCode
+func Parse(input string) *Node { return DefaultRegistry.Parse(input) }
+
+// Parse 解析 BBCode 为语法树
+// 未注册的标签和无法匹配的结束标签按文本保留, 未闭合的标签在父标签结束或输入结束时自动闭合
+func (r *Registry) Parse(input string) *Node {
+	root := &Node{Type: DocumentNode}
+	stack := []*Node{root}
+	top := func() *Node { return stack[len(stack)-1] }
+
+	tokens := Tokenize(input)
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		tag, known := r.Lookup(tok.Name)
+		if tok.Type == TextToken || !known {
+			top().appendChild(&Node{Type: TextNode, Text: tok.Raw})
+			continue
+		}
+
+		if tok.Type == CloseToken {
+			// 没有结束标签的标签, 如 [hr][/hr], 忽略结束标签
+			if tag.Void {
+				continue
+			}
+			idx := findOpen(stack, tok.Name, false)
+			if idx < 0 {
+				// 没有对应的开始标签, 按文本保留
+				top().appendChild(&Node{Type: TextNode, Text: tok.Raw})
+				continue
+			}
+			stack = stack[:idx]
+			continue
+		}
+
+		// 同名标签自动闭合上一个, 不跨越容器
+		if tag.AutoClose {
+			if idx := findOpen(stack, tok.Name, true); idx >= 0 {
+				stack = stack[:idx]
+			}
+		}
+
+		node := &Node{Type: TagNode, Tag: tag, Name: tok.Name, Value: tok.Value, Attrs: tok.Attrs}
+		top().appendChild(node)
+
+		switch {
+		case tag.Void:
+		case tag.Raw:
+			// 直到对应的结束标签前都按原文保存
+			var buf strings.Builder
+			for i++; i < len(tokens); i++ {
+				if tokens[i].Type == CloseToken && tokens[i].Name == tok.Name {
+					break
+				}
+				buf.WriteString(tokens[i].Raw)
+			}
+			node.Text = buf.String()
+		default:
+			stack = append(stack, node)
+		}
+	}
+	return root
+}
+
+// 在栈中查找最近的同名开始标签, stopAtContainer 为 true 时遇到容器停止
+func findOpen(stack []*Node, name string, stopAtContainer bool) int {
+	for i := len(stack) - 1; i > 0; i-- {
+		if stack[i].Name == name {
+			return i
+		}
+		if stopAtContainer && stack[i].Tag.Container {
+			return -1
+		}
+	}
+	return -1
+}
@@ -0,0 +1,110 @@
+package bbcode
+
+/*
+ * @Desc: BBCode 渲染
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Format 输出格式
+type Format int
+
+const (
+	FormatHTML     Format = iota // HTML
+	FormatText                   // 纯文本
+	FormatMarkdown               // Markdown
+)
+
+// 连续空行
+var blankLinePattern = regexp.MustCompile(`\n{3,}`)
+
+// HTML 将 BBCode 转换为 HTML, 文本中原有的 HTML 原样保留
+func HTML(input string) string { return Render(Parse(input), FormatHTML) }
+
+// Text 将 BBCode 转换为纯文本
+func Text(input string) string {
+	return strings.TrimSpace(blankLinePattern.ReplaceAllString(Render(Parse(input), FormatText), "\n\n"))
+}
+
+// Markdown 将 BBCode 转换为 Markdown
+func Markdown(input string) string {
+	return strings.TrimSpace(blankLinePattern.ReplaceAllString(Render(Parse(input), FormatMarkdown), "\n\n"))
+}
+
+// Render 按格式渲染语法树
+func Render(n *Node, f Format) string {
+	if n.Type == TextNode {
+		return renderText(n.Text, f)
+	}
+
+	var buf strings.Builder
+	for i, child := range n.Children {
+		if child.Type == TextNode {
+			if text := trimBlockSpace(n, i); text != "" {
+				buf.WriteString(renderText(text, f))
+			}
+			continue
+		}
+		buf.WriteString(Render(child, f))
+	}
+	content := buf.String()
+	if n.Type == DocumentNode {
+		return content
+	}
+
+	var fn RenderFunc
+	switch f {
+	case FormatHTML:
+		fn = n.Tag.HTML
+	case FormatText:
+		fn = n.Tag.Text
+	case FormatMarkdown:
+		fn = n.Tag.Markdown
+	}
+	if fn == nil {
+		return content
+	}
+	return fn(n, content)
+}
+
+// 文本节点输出, HTML 中换行转换为 <br>
+func renderText(text string, f Format) string {
+	if f == FormatHTML {
+		return strings.ReplaceAll(text, "\n", "<br>")
+	}
+	return text
+}
+
+// 去掉块级标签前后的一个换行, 容器中的空白文本直接忽略
+func trimBlockSpace(parent *Node, i int) string {
+	text := parent.Children[i].Text
+	if parent.Type == TagNode && parent.Tag.Container && strings.TrimSpace(text) == "" {
+		return ""
+	}
+	if i == 0 && parent.Type == TagNode && parent.Tag.Block || i > 0 && isBlock(parent.Children[i-1]) {
+		text = trimNewline(text, true)
+	}
+	if i == len(parent.Children)-1 && parent.Type == TagNode && parent.Tag.Block || i < len(parent.Children)-1 && isBlock(parent.Children[i+1]) {
+		text = trimNewline(text, false)
+	}
+	return text
+}
+
+func isBlock(n *Node) bool { return n.Type == TagNode && n.Tag.Block }
+
+// 去掉开头或结尾的一个换行
+func trimNewline(text string, leading bool) string {
+	if leading {
+		text = strings.TrimLeft(text, " \t")
+		text = strings.TrimPrefix(text, "\r")
+		return strings.TrimPrefix(text, "\n")
+	}
+	text = strings.TrimRight(text, " \t")
+	text = strings.TrimSuffix(text, "\n")
+	return strings.TrimSuffix(text, "\r")
+}
@@ -0,0 +1,67 @@
+package bbcode
+
+/*
+ * @Desc: BBCode 标签注册
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+import (
+	"strings"
+	"sync"
+)
+
+// RenderFunc 标签渲染函数, content 为已渲染的子节点
+type RenderFunc func(n *Node, content string) string
+
+// Tag 标签定义
+type Tag struct {
+	Name      string
+	Block     bool // 块级标签, 前后的换行不再输出
+	Container bool // 只包含子标签的容器, 如 [list] [table], 其中的空白文本忽略
+	AutoClose bool // 再次出现同名标签时自动闭合上一个, 如 [*]
+	Void      bool // 没有结束标签, 如 [hr]
+	Raw       bool // 内容不解析标签, 如 [code] [noparse]
+
+	HTML     RenderFunc // 为空时只输出内容
+	Text     RenderFunc // 为空时只输出内容
+	Markdown RenderFunc // 为空时只输出内容
+}
+
+// Registry 标签注册表
+type Registry struct {
+	mu   sync.RWMutex
+	tags map[string]*Tag
+}
+
+// NewRegistry 创建空的标签注册表
+func NewRegistry() *Registry {
+	return &Registry{tags: make(map[string]*Tag)}
+}
+
+// Register 注册标签, 同名标签覆盖
+func (r *Registry) Register(tags ...*Tag) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tag := range tags {
+		r.tags[strings.ToLower(tag.Name)] = tag
+	}
+}
+
+// Lookup 查找标签定义
+func (r *Registry) Lookup(name string) (*Tag, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tag, ok := r.tags[name]
+	return tag, ok
+}
+
+// DefaultRegistry 默认标签注册表, 包含 Steam 公告使用的标签
+var DefaultRegistry = NewRegistry()
+
+// Register 向默认注册表注册标签
+func Register(tags ...*Tag) { DefaultRegistry.Register(tags...) }
+
+func init() {
+	Register(defaultTags()...)
+}
@@ -0,0 +1,352 @@
+package bbcode
+
+/*
+ * @Desc: BBCode 默认标签
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+import (
+	"html"
+	"net/url"
+	"strings"
+)
+
+// 转义属性值
+func attr(s string) string { return html.EscapeString(strings.TrimSpace(s)) }
+
+// 给每一行加上前缀
+func prefixLines(s string, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i := range lines {
+		if lines[i] == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+			continue
+		}
+		lines[i] = prefix + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// 包裹 HTML 标签
+func wrapHTML(name string) RenderFunc {
+	return func(n *Node, content string) string { return "<" + name + ">" + content + "</" + name + ">" }
+}
+
+// 包裹 Markdown 标记, 首尾空白移到标记外, 否则标记不生效
+func wrapMarkdown(mark string) RenderFunc {
+	return func(n *Node, content string) string {
+		trimmed := strings.TrimSpace(content)
+		if trimmed == "" {
+			return content
+		}
+		start := strings.Index(content, trimmed)
+		return content[:start] + mark + trimmed + mark + content[start+len(trimmed):]
+	}
+}
+
+// 块级内容前后空行
+func blockText(n *Node, content string) string { return "\n" + content + "\n" }
+
+// 标题
+func headingTag(name string, level int) *Tag {
+	return &Tag{
+		Name:  name,
+		Block: true,
+		HTML:  wrapHTML(name),
+		Text:  blockText,
+		Markdown: func(n *Node, content string) string {
+			return "\n\n" + strings.Repeat("#", level) + " " + strings.TrimSpace(content) + "\n\n"
+		},
+	}
+}
+
+// 允许输出的链接协议, 其余协议(如 javascript: data:)的链接和媒体不输出
+var allowedSchemes = map[string]bool{
+	"http":  true,
+	"https": true,
+	"steam": true,
+}
+
+// 校验地址协议, 不允许的地址返回空, 不依赖后续的 HTML 过滤
+func safeURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || !allowedSchemes[strings.ToLower(u.Scheme)] {
+		return ""
+	}
+	return raw
+}
+
+// Markdown 中的地址, 转义会提前结束链接的字符
+var markdownURLReplacer = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E")
+
+func markdownURL(href string) string { return markdownURLReplacer.Replace(href) }
+
+// 链接地址, 没有参数时取标签内容, 协议不允许时返回空
+func linkHref(n *Node) string {
+	href := n.Value
+	if href == "" {
+		href = n.Attr("href")
+	}
+	if href == "" {
+		href = n.PlainText()
+	}
+	return safeURL(href)
+}
+
+// 图片地址, 支持 [img]url[/img] 和 [img src="url"][/img], 协议不允许时返回空
+func imageSrc(n *Node) string {
+	src := n.Attr("src")
+	if src == "" {
+		src = n.Value
+	}
+	if src == "" {
+		src = n.PlainText()
+	}
+	return safeURL(src)
+}
+
+// YouTube 视频id, [previewyoutube=id;full] 中 ; 之后为显示方式
+func youtubeID(n *Node) string {
+	id := n.Value
+	if id == "" {
+		id = strings.TrimSpace(n.PlainText())
+	}
+	id, _, _ = strings.Cut(id, ";")
+	return id
+}
+
+func youtubeHTML(n *Node, content string) string {
+	return `<iframe src="https://www.youtube.com/embed/` + attr(youtubeID(n)) + `" frameborder="0" allowfullscreen></iframe>`
+}
+
+func youtubeLink(n *Node, content string) string {
+	return "\nhttps://www.youtube.com/watch?v=" + youtubeID(n) + "\n"
+}
+
+// 视频地址, Steam 使用 [video mp4="" webm="" poster=""][/video], 跳过协议不允许的地址
+func videoSources(n *Node) [][2]string {
+	var sources [][2]string
+	for _, typ := range []string{"webm", "mp4"} {
+		if src := safeURL(n.Attr(typ)); src != "" {
+			sources = append(sources, [2]string{src, "video/" + typ})
+		}
+	}
+	if len(sources) == 0 {
+		if src := safeURL(n.PlainText()); src != "" {
+			sources = append(sources, [2]string{src, ""})
+		}
+	}
+	return sources
+}
+
+// 列表项所在的列表
+func listParent(n *Node) string {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Name == "list" || p.Name == "olist" {
+			return p.Name
+		}
+	}
+	return "list"
+}
+
+// 表格行的单元格数
+func rowCells(n *Node) int {
+	cnt := 0
+	for _, child := range n.Children {
+		if child.Name == "td" || child.Name == "th" {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// 是否为表格的第一行
+func isFirstRow(n *Node) bool {
+	if n.Parent == nil {
+		return true
+	}
+	for _, child := range n.Parent.Children {
+		if child.Name == "tr" {
+			return child == n
+		}
+	}
+	return true
+}
+
+// 默认标签
+func defaultTags() []*Tag {
+	return []*Tag{
+		// 文本样式
+		{Name: "b", HTML: wrapHTML("strong"), Markdown: wrapMarkdown("**")},
+		{Name: "i", HTML: wrapHTML("em"), Markdown: wrapMarkdown("*")},
+		{Name: "u", HTML: wrapHTML("u")},
+		{Name: "s", HTML: wrapHTML("s"), Markdown: wrapMarkdown("~~")},
+		{Name: "strike", HTML: wrapHTML("s"), Markdown: wrapMarkdown("~~")},
+		{Name: "spoiler", HTML: func(n *Node, content string) string {
+			return `<span class="bb_spoiler">` + content + `</span>`
+		}, Markdown: wrapMarkdown("||")},
+		{Name: "emoticon", HTML: func(n *Node, content string) string {
+			return ":" + content + ":"
+		}, Text: func(n *Node, content string) string {
+			return ":" + content + ":"
+		}, Markdown: func(n *Node, content string) string {
+			return ":" + content + ":"
+		}},
+		{Name: "noparse", Raw: true, HTML: func(n *Node, content string) string {
+			return html.EscapeString(n.Text)
+		}, Text: func(n *Node, content string) string {
+			return n.Text
+		}, Markdown: func(n *Node, content string) string {
+			return n.Text
+		}},
+
+		// 标题和段落
+		headingTag("h1", 1),
+		headingTag("h2", 2),
+		headingTag("h3", 3),
+		{Name: "p", Block: true, HTML: wrapHTML("p"), Text: blockText, Markdown: func(n *Node, content string) string {
+			return "\n\n" + content + "\n\n"
+		}},
+		{Name: "hr", Block: true, Void: true, HTML: func(n *Node, content string) string {
+			return "<hr>"
+		}, Text: func(n *Node, content string) string {
+			return "\n----\n"
+		}, Markdown: func(n *Node, content string) string {
+			return "\n\n---\n\n"
+		}},
+		{Name: "quote", Block: true, HTML: func(n *Node, content string) string {
+			author := n.Value
+			if author == "" {
+				author = n.Attr("author")
+			}
+			if author != "" {
+				return "<blockquote><cite>" + attr(author) + "</cite>" + content + "</blockquote>"
+			}
+			return "<blockquote>" + content + "</blockquote>"
+		}, Text: blockText, Markdown: func(n *Node, content string) string {
+			return "\n\n" + prefixLines(strings.TrimSpace(content), "> ") + "\n\n"
+		}},
+		{Name: "code", Block: true, Raw: true, HTML: func(n *Node, content string) string {
+			return "<pre><code>" + html.EscapeString(n.Text) + "</code></pre>"
+		}, Text: func(n *Node, content string) string {
+			return "\n" + n.Text + "\n"
+		}, Markdown: func(n *Node, content string) string {
+			return "\n\n```\n" + strings.Trim(n.Text, "\n") + "\n```\n\n"
+		}},
+
+		// 链接和媒体
+		// 协议不允许的链接只输出内容
+		{Name: "url", HTML: func(n *Node, content string) string {
+			if href := linkHref(n); href != "" {
+				return `<a href="` + attr(href) + `">` + content + `</a>`
+			}
+			return content
+		}, Text: func(n *Node, content string) string {
+			if href := linkHref(n); href != "" && href != strings.TrimSpace(content) {
+				return content + " (" + href + ")"
+			}
+			return content
+		}, Markdown: func(n *Node, content string) string {
+			if href := linkHref(n); href != "" {
+				return "[" + content + "](" + markdownURL(href) + ")"
+			}
+			return content
+		}},
+		{Name: "dynamiclink", HTML: func(n *Node, content string) string {
+			if href := linkHref(n); href != "" {
+				return `<a href="` + attr(href) + `">` + html.EscapeString(href) + `</a>`
+			}
+			return ""
+		}, Text: func(n *Node, content string) string {
+			return linkHref(n)
+		}, Markdown: func(n *Node, content string) string {
+			if href := linkHref(n); href != "" {
+				return "<" + markdownURL(href) + ">"
+			}
+			return ""
+		}},
+		{Name: "img", HTML: func(n *Node, content string) string {
+			if src := imageSrc(n); src != "" {
+				return `<img src="` + attr(src) + `" />`
+			}
+			return ""
+		}, Text: func(n *Node, content string) string {
+			return ""
+		}, Markdown: func(n *Node, content string) string {
+			if src := imageSrc(n); src != "" {
+				return "![](" + markdownURL(src) + ")"
+			}
+			return ""
+		}},
+		{Name: "video", Block: true, HTML: func(n *Node, content string) string {
+			var buf strings.Builder
+			buf.WriteString("<video controls")
+			if poster := safeURL(n.Attr("poster")); poster != "" {
+				buf.WriteString(` poster="` + attr(poster) + `"`)
+			}
+			buf.WriteString(">")
+			for _, v := range videoSources(n) {
+				buf.WriteString(`<source src="` + attr(v[0]) + `"`)
+				if v[1] != "" {
+					buf.WriteString(` type="` + v[1] + `"`)
+				}
+				buf.WriteString(" />")
+			}
+			buf.WriteString("</video>")
+			return buf.String()
+		}, Text: func(n *Node, content string) string {
+			return ""
+		}, Markdown: func(n *Node, content string) string {
+			if sources := videoSources(n); len(sources) > 0 {
+				return "\n" + markdownURL(sources[0][0]) + "\n"
+			}
+			return ""
+		}},
+		{Name: "youtube", Block: true, HTML: youtubeHTML, Text: youtubeLink, Markdown: youtubeLink},
+		{Name: "previewyoutube", Block: true, HTML: youtubeHTML, Text: youtubeLink, Markdown: youtubeLink},
+
+		// 列表
+		{Name: "list", Block: true, Container: true, HTML: wrapHTML("ul"), Text: blockText, Markdown: func(n *Node, content string) string {
+			return "\n\n" + content + "\n"
+		}},
+		{Name: "olist", Block: true, Container: true, HTML: wrapHTML("ol"), Text: blockText, Markdown: func(n *Node, content string) string {
+			return "\n\n" + content + "\n"
+		}},
+		{Name: "*", Block: true, AutoClose: true, HTML: wrapHTML("li"), Text: func(n *Node, content string) string {
+			// 嵌套列表缩进
+			return "- " + strings.TrimPrefix(prefixLines(strings.TrimSpace(content), "  "), "  ") + "\n"
+		}, Markdown: func(n *Node, content string) string {
+			mark := "- "
+			if listParent(n) == "olist" {
+				mark = "1. "
+			}
+			return mark + strings.TrimPrefix(prefixLines(strings.TrimSpace(content), "  "), "  ") + "\n"
+		}},
+
+		// 表格
+		{Name: "table", Block: true, Container: true, HTML: wrapHTML("table"), Text: blockText, Markdown: func(n *Node, content string) string {
+			return "\n\n" + content + "\n"
+		}},
+		{Name: "tr", Block: true, Container: true, HTML: wrapHTML("tr"), Text: func(n *Node, content string) string {
+			return strings.TrimSuffix(content, "\t") + "\n"
+		}, Markdown: func(n *Node, content string) string {
+			row := "|" + content + "\n"
+			if isFirstRow(n) {
+				row += strings.Repeat("| --- ", rowCells(n)) + "|\n"
+			}
+			return row
+		}},
+		{Name: "th", HTML: wrapHTML("th"), Text: func(n *Node, content string) string {
+			return strings.TrimSpace(content) + "\t"
+		}, Markdown: func(n *Node, content string) string {
+			return " " + strings.ReplaceAll(strings.TrimSpace(content), "\n", " ") + " |"
+		}},
+		{Name: "td", HTML: wrapHTML("td"), Text: func(n *Node, content string) string {
+			return strings.TrimSpace(content) + "\t"
+		}, Markdown: func(n *Node, content string) string {
+			return " " + strings.ReplaceAll(strings.TrimSpace(content), "\n", " ") + " |"
+		}},
+	}
+}
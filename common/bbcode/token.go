@@ -0,0 +1,187 @@
+package bbcode
+
+/*
+ * @Desc: BBCode 词法分析
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+import (
+	"strings"
+)
+
+// TokenType 词法单元类型
+type TokenType int
+
+const (
+	TextToken  TokenType = iota // 普通文本
+	OpenToken                   // 开始标签 [b] [url=...] [img src="..."]
+	CloseToken                  // 结束标签 [/b]
+)
+
+// Token 词法单元
+type Token struct {
+	Type  TokenType
+	Name  string            // 标签名, 小写
+	Value string            // [name=value] 形式的参数
+	Attrs map[string]string // [name key="value"] 形式的属性
+	Raw   string            // 原始文本, 标签无法识别时按文本输出
+}
+
+// Tokenize 将输入拆分为文本和标签, 不检查标签是否注册
+func Tokenize(input string) []Token {
+	var tokens []Token
+	var text strings.Builder
+	flushText := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, Token{Type: TextToken, Raw: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(input); {
+		if input[i] != '[' {
+			next := strings.IndexByte(input[i:], '[')
+			if next < 0 {
+				text.WriteString(input[i:])
+				break
+			}
+			text.WriteString(input[i : i+next])
+			i += next
+			continue
+		}
+
+		end := findTagEnd(input, i+1)
+		if end < 0 {
+			text.WriteByte('[')
+			i++
+			continue
+		}
+		raw := input[i : end+1]
+		tok, ok := parseTag(input[i+1 : end])
+		if !ok {
+			text.WriteByte('[')
+			i++
+			continue
+		}
+		tok.Raw = raw
+		flushText()
+		tokens = append(tokens, tok)
+		i = end + 1
+	}
+	flushText()
+	return tokens
+}
+
+// 查找标签的结束位置, 引号中的 ] 不作为结束, 遇到换行或新的 [ 视为不是标签
+func findTagEnd(input string, start int) int {
+	var quote byte
+	for i := start; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\n' {
+				return -1
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ']':
+			return i
+		case c == '[' || c == '\n':
+			return -1
+		}
+	}
+	return -1
+}
+
+// 解析标签内容, 即 [] 之间的部分
+func parseTag(content string) (Token, bool) {
+	if strings.HasPrefix(content, "/") {
+		name := strings.ToLower(strings.TrimSpace(content[1:]))
+		if !validName(name) {
+			return Token{}, false
+		}
+		return Token{Type: CloseToken, Name: name}, true
+	}
+
+	nameEnd := strings.IndexAny(content, "= ")
+	if nameEnd < 0 {
+		nameEnd = len(content)
+	}
+	name := strings.ToLower(content[:nameEnd])
+	if !validName(name) {
+		return Token{}, false
+	}
+	tok := Token{Type: OpenToken, Name: name}
+
+	rest := content[nameEnd:]
+	switch {
+	case strings.HasPrefix(rest, "="):
+		tok.Value = unquote(strings.TrimSpace(rest[1:]))
+	case strings.TrimSpace(rest) != "":
+		tok.Attrs = parseAttrs(rest)
+	}
+	return tok, true
+}
+
+// 解析 key="value" key=value 形式的属性
+func parseAttrs(s string) map[string]string {
+	attrs := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			return attrs
+		}
+		eq := strings.IndexAny(s, "= ")
+		if eq < 0 || s[eq] == ' ' {
+			// 没有值的属性
+			key := s
+			if eq >= 0 {
+				key, s = s[:eq], s[eq:]
+			} else {
+				s = ""
+			}
+			attrs[strings.ToLower(key)] = ""
+			continue
+		}
+
+		key := strings.ToLower(s[:eq])
+		s = s[eq+1:]
+		var value string
+		if s != "" && (s[0] == '"' || s[0] == '\'') {
+			if end := strings.IndexByte(s[1:], s[0]); end >= 0 {
+				value, s = s[1:end+1], s[end+2:]
+			} else {
+				value, s = s[1:], ""
+			}
+		} else if end := strings.IndexByte(s, ' '); end >= 0 {
+			value, s = s[:end], s[end:]
+		} else {
+			value, s = s, ""
+		}
+		attrs[key] = value
+	}
+}
+
+// 去掉参数两侧的引号
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// 标签名只允许字母、数字和 *
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '*' {
+			return false
+		}
+	}
+	return true
+}
@@ -16,6 +16,7 @@ const (
 	RETURN_FAILED           = 0                  //失败
 	RETURN_SUCCESS          = 1                  //成功
 	RETURN_RECORD_NOT_FOUND = "record-not-found" //未找到
+	RETURN_NOT_SUPPORTED    = "not-supported"    //不支持
 )
 
 // 时间
@@ -0,0 +1,262 @@
+package sanitize
+
+/*
+ * @Desc: 富文本 HTML 过滤
+ * @author: 福狼
+ * @version: v1.0.0
+ */
+
+import (
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Policy 白名单过滤策略
+type Policy struct {
+	Tags        map[string][]string // 允许的标签及其属性
+	GlobalAttrs []string            // 所有允许的标签都可使用的属性
+	Schemes     []string            // 链接允许的协议, 相对地址始终允许
+	IframeHosts []string            // iframe 允许的域名, 包含子域名
+}
+
+// 连同内容一起删除的标签
+var dropContentTags = map[string]bool{
+	"script":   true,
+	"style":    true,
+	"noscript": true,
+	"template": true,
+	"object":   true,
+	"embed":    true,
+	"head":     true,
+	"title":    true,
+	"textarea": true,
+	"select":   true,
+}
+
+// 没有结束标签的标签
+var voidTags = map[string]bool{
+	"br":     true,
+	"hr":     true,
+	"img":    true,
+	"source": true,
+	"wbr":    true,
+	"col":    true,
+	"track":  true,
+}
+
+// 值为链接的属性
+var urlAttrs = map[string]bool{
+	"href":   true,
+	"src":    true,
+	"poster": true,
+	"cite":   true,
+}
+
+// DefaultPolicy 默认策略, 覆盖 Steam 商店描述和公告中使用的标签
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Tags: map[string][]string{
+			"a":          {"href", "title"},
+			"abbr":       {"title"},
+			"b":          nil,
+			"blockquote": {"cite"},
+			"br":         nil,
+			"cite":       nil,
+			"code":       nil,
+			"del":        nil,
+			"div":        nil,
+			"em":         nil,
+			"figcaption": nil,
+			"figure":     nil,
+			"h1":         nil,
+			"h2":         nil,
+			"h3":         nil,
+			"h4":         nil,
+			"h5":         nil,
+			"h6":         nil,
+			"hr":         nil,
+			"i":          nil,
+			"iframe":     {"src", "width", "height", "frameborder", "allowfullscreen"},
+			"img":        {"src", "alt", "title", "width", "height"},
+			"li":         nil,
+			"ol":         nil,
+			"p":          nil,
+			"pre":        nil,
+			"s":          nil,
+			"small":      nil,
+			"source":     {"src", "type"},
+			"span":       nil,
+			"strike":     nil,
+			"strong":     nil,
+			"sub":        nil,
+			"sup":        nil,
+			"table":      nil,
+			"tbody":      nil,
+			"td":         {"colspan", "rowspan"},
+			"th":         {"colspan", "rowspan"},
+			"thead":      nil,
+			"tr":         nil,
+			"u":          nil,
+			"ul":         nil,
+			"video":      {"src", "poster", "controls", "muted", "loop", "playsinline"},
+		},
+		GlobalAttrs: []string{"class"},
+		Schemes:     []string{"http", "https", "mailto"},
+		IframeHosts: []string{"youtube.com", "youtube-nocookie.com", "player.bilibili.com"},
+	}
+}
+
+// 标签是否允许该属性
+func (p *Policy) allowAttr(tag string, key string) bool {
+	for _, v := range p.GlobalAttrs {
+		if v == key {
+			return true
+		}
+	}
+	for _, v := range p.Tags[tag] {
+		if v == key {
+			return true
+		}
+	}
+	return false
+}
+
+// 链接是否使用允许的协议
+func (p *Policy) allowURL(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" {
+		return true
+	}
+	for _, v := range p.Schemes {
+		if strings.EqualFold(v, u.Scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// iframe 地址是否在允许的域名下
+func (p *Policy) allowIframe(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, v := range p.IframeHosts {
+		v = strings.ToLower(v)
+		if host == v || strings.HasSuffix(host, "."+v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sanitize 按策略过滤 HTML
+// 不允许的标签去掉标签保留内容, 脚本类标签连同内容删除, 未闭合的标签在结尾补齐
+func (p *Policy) Sanitize(input string) string {
+	z := html.NewTokenizer(strings.NewReader(input))
+	var buf strings.Builder
+	var stack []string // 已输出未闭合的标签
+	skipTag, skipDepth := "", 0
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			// 输入结束, 补齐未闭合的标签
+			for i := len(stack) - 1; i >= 0; i-- {
+				buf.WriteString("</" + stack[i] + ">")
+			}
+			return buf.String()
+
+		case html.TextToken:
+			if skipDepth == 0 {
+				buf.WriteString(html.EscapeString(string(z.Text())))
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			name := tok.Data
+			if skipDepth > 0 {
+				if name == skipTag && tt == html.StartTagToken {
+					skipDepth++
+				}
+				continue
+			}
+
+			// 不允许的 iframe 连同备用内容一起删除
+			_, allowed := p.Tags[name]
+			dropContent := dropContentTags[name] || name == "iframe"
+			if name == "iframe" && allowed && !p.allowIframe(attrValue(tok, "src")) {
+				allowed = false
+			}
+			if !allowed {
+				// 浏览器忽略非空标签的自闭合写法, <script/> 之后的内容同样作为脚本删除
+				if dropContent && !voidTags[name] {
+					skipTag, skipDepth = name, 1
+				}
+				continue
+			}
+
+			buf.WriteString("<" + name)
+			for _, a := range tok.Attr {
+				key := strings.ToLower(a.Key)
+				if a.Namespace != "" || !p.allowAttr(name, key) {
+					continue
+				}
+				if urlAttrs[key] && !p.allowURL(a.Val) {
+					continue
+				}
+				buf.WriteString(" " + key + `="` + html.EscapeString(a.Val) + `"`)
+			}
+			if name == "a" {
+				buf.WriteString(` rel="nofollow noopener noreferrer"`)
+			}
+			if voidTags[name] {
+				buf.WriteString(" />")
+				continue
+			}
+			buf.WriteString(">")
+			if tt == html.SelfClosingTagToken {
+				buf.WriteString("</" + name + ">")
+				continue
+			}
+			stack = append(stack, name)
+
+		case html.EndTagToken:
+			name := z.Token().Data
+			if skipDepth > 0 {
+				if name == skipTag {
+					skipDepth--
+				}
+				continue
+			}
+			// 只闭合已输出的标签, 中间未闭合的一并闭合
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i] != name {
+					continue
+				}
+				for j := len(stack) - 1; j >= i; j-- {
+					buf.WriteString("</" + stack[j] + ">")
+				}
+				stack = stack[:i]
+				break
+			}
+		}
+	}
+}
+
+// 获取标签属性值
+func attrValue(tok html.Token, key string) string {
+	for _, a := range tok.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
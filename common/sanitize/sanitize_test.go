@@ -0,0 +1,128 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		// 链接协议
+		{"https 链接", `<a href="https://example.com">x</a>`, `<a href="https://example.com" rel="nofollow noopener noreferrer">x</a>`},
+		{"相对地址", `<a href="/app/1">x</a>`, `<a href="/app/1" rel="nofollow noopener noreferrer">x</a>`},
+		{"mailto", `<a href="mailto:a@b.c">x</a>`, `<a href="mailto:a@b.c" rel="nofollow noopener noreferrer">x</a>`},
+		{"javascript 链接", `<a href="javascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"大小写混合", `<a href="JaVaScRiPt:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"前导空白", `<a href="  javascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"前导换行", "<a href=\"\n\tjavascript:alert(1)\">x</a>", `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"实体编码", `<a href="&#106;avascript:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"协议中插入制表符", `<a href="java&#x09;script:alert(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"data 图片", `<img src="data:image/svg+xml;base64,PHN2Zz4=">`, `<img />`},
+		{"data 大小写", `<img src=" DATA:text/html,x">`, `<img />`},
+		{"vbscript", `<a href="vbscript:msgbox(1)">x</a>`, `<a rel="nofollow noopener noreferrer">x</a>`},
+		{"video poster", `<video poster="javascript:alert(1)" src="https://v.example.com/a.webm"></video>`, `<video src="https://v.example.com/a.webm"></video>`},
+
+		// 事件属性和未允许的属性
+		{"onerror", `<img src="https://example.com/a.png" onerror="alert(1)">`, `<img src="https://example.com/a.png" />`},
+		{"onclick", `<a href="https://example.com" onclick="alert(1)">x</a>`, `<a href="https://example.com" rel="nofollow noopener noreferrer">x</a>`},
+		{"大写事件属性", `<p ONMOUSEOVER="alert(1)">x</p>`, `<p>x</p>`},
+		{"style", `<span style="background:url(javascript:alert(1))">x</span>`, `<span>x</span>`},
+		{"class 保留", `<span class="bb_spoiler">x</span>`, `<span class="bb_spoiler">x</span>`},
+		{"属性值转义", `<a title="&quot;><script>" href="https://example.com">x</a>`, `<a title="&#34;&gt;&lt;script&gt;" href="https://example.com" rel="nofollow noopener noreferrer">x</a>`},
+
+		// iframe 域名
+		{"youtube iframe", `<iframe src="https://www.youtube.com/embed/abc" allowfullscreen></iframe>`, `<iframe src="https://www.youtube.com/embed/abc" allowfullscreen=""></iframe>`},
+		{"bilibili iframe", `<iframe src="https://player.bilibili.com/player.html?bvid=1"></iframe>`, `<iframe src="https://player.bilibili.com/player.html?bvid=1"></iframe>`},
+		{"其他域名 iframe", `<iframe src="https://evil.com/"><b>fallback</b></iframe>after`, `after`},
+		{"仿冒域名", `<iframe src="https://evil-youtube.com/embed/abc"></iframe>`, ``},
+		{"域名后缀", `<iframe src="https://youtube.com.evil.com/embed/abc"></iframe>`, ``},
+		{"域名在路径中", `<iframe src="https://evil.com/youtube.com/embed"></iframe>`, ``},
+		{"域名在用户信息中", `<iframe src="https://youtube.com@evil.com/embed"></iframe>`, ``},
+		{"协议相对地址", `<iframe src="//www.youtube.com/embed/abc"></iframe>`, ``},
+		{"javascript iframe", `<iframe src="javascript:alert(1)"></iframe>`, ``},
+		{"没有地址的 iframe", `<iframe srcdoc="<script>alert(1)</script>"></iframe>`, ``},
+
+		// 删除内容的标签
+		{"script", `a<script>alert(1)</script>b`, `ab`},
+		{"style", `a<style>body{}</style>b`, `ab`},
+		{"object 中的 script", `a<object><script>alert(1)</script><b>x</b></object>b`, `ab`},
+		{"嵌套 object", `a<object><object></object><script>alert(1)</script></object>b`, `ab`},
+		{"noscript 中的 script", `a<noscript><p><script>alert(1)</script></p></noscript>b`, `ab`},
+		{"textarea 中的标签", `a<textarea></textarea><script>alert(1)</script></textarea>b`, `ab`},
+		{"未允许的标签保留内容", `<font color="red"><b>x</b></font>`, `<b>x</b>`},
+		{"未允许标签中的 script", `<form action="https://evil.com"><script>alert(1)</script><p>x</p></form>`, `<p>x</p>`},
+		{"svg", `<svg onload="alert(1)"><script>alert(2)</script></svg>`, ``},
+		{"未闭合的 script", `a<script>alert(1)`, `a`},
+
+		// 未闭合和错误嵌套
+		{"未闭合", `<b>bold<i>italic`, `<b>bold<i>italic</i></b>`},
+		{"错误嵌套", `<b><i>x</b>y</i>`, `<b><i>x</i></b>y`},
+		{"多余的结束标签", `x</div></b>y`, `xy`},
+		{"未输出标签的结束标签", `<p><font>x</p></font>`, `<p>x</p>`},
+		{"列表未闭合", `<ul><li>a<li>b</ul>c`, `<ul><li>a<li>b</li></li></ul>c`},
+
+		// 自闭合
+		{"自闭合的非空标签", `<div/>x`, `<div></div>x`},
+		{"自闭合的 b", `<b/>x`, `<b></b>x`},
+		{"空标签", `<br>a<br/>b<hr>`, `<br />a<br />b<hr />`},
+		{"img 结束标签", `<img src="https://example.com/a.png"></img>x`, `<img src="https://example.com/a.png" />x`},
+		{"自闭合的 script", `a<script/>b`, `a`},
+
+		// 文本转义
+		{"文本转义", `1 < 2 & 3 > 2`, `1 &lt; 2 &amp; 3 &gt; 2`},
+		{"注释", `a<!-- <script>alert(1)</script> -->b`, `ab`},
+	}
+
+	p := DefaultPolicy()
+	for _, c := range cases {
+		if got := p.Sanitize(c.input); got != c.want {
+			t.Errorf("%s: Sanitize(%q)\n got  %q\n want %q", c.name, c.input, got, c.want)
+		}
+	}
+}
+
+// 输出中不应出现可执行的内容
+func TestSanitizeNoScript(t *testing.T) {
+	inputs := []string{
+		`<scr<script>ipt>alert(1)</script>`,
+		`<<script>script>alert(1)<</script>/script>`,
+		`<img src=x onerror=alert(1)//`,
+		`<a href="javas&#99;ript:alert(1)">x</a>`,
+		`<iframe src="https://www.youtube.com/embed/x" onload="alert(1)"></iframe>`,
+		`<math><mi xlink:href="javascript:alert(1)">x</mi></math>`,
+		`<svg><a xlink:href="javascript:alert(1)"><text>x</text></a></svg>`,
+	}
+	p := DefaultPolicy()
+	for _, input := range inputs {
+		got := strings.ToLower(p.Sanitize(input))
+		for _, bad := range []string{"<script", "javascript:", "onerror", "onload", "xlink"} {
+			if strings.Contains(got, bad) {
+				t.Errorf("Sanitize(%q) = %q, contains %q", input, got, bad)
+			}
+		}
+	}
+}
+
+func TestCustomPolicy(t *testing.T) {
+	p := &Policy{
+		Tags:        map[string][]string{"a": {"href"}, "iframe": {"src"}},
+		Schemes:     []string{"https", "steam"},
+		IframeHosts: []string{"example.com"},
+	}
+	cases := map[string]string{
+		`<a href="steam://store/1">x</a>`:                         `<a href="steam://store/1" rel="nofollow noopener noreferrer">x</a>`,
+		`<a href="http://example.com">x</a>`:                      `<a rel="nofollow noopener noreferrer">x</a>`,
+		`<iframe src="https://video.example.com/1"></iframe>`:     `<iframe src="https://video.example.com/1"></iframe>`,
+		`<iframe src="https://www.youtube.com/embed/1"></iframe>`: ``,
+		`<b>x</b>`: `x`,
+	}
+	for input, want := range cases {
+		if got := p.Sanitize(input); got != want {
+			t.Errorf("Sanitize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -166,6 +166,16 @@ func HDel(key string, fields ...string) (res int64, gfsError common.GFError) {
 	return intVal, nil
 }
 
+// 发布消息到频道
+func Publish(channel string, message any) common.GFError {
+	err := client.Publish(ctx, channel, message).Err()
+	if err != nil {
+		log.Error("发布消息失败..." + err.Error())
+		return common.NewServiceError("发布消息失败.")
+	}
+	return nil
+}
+
 func Incr(key string) {
 	client.Incr(ctx, key)
 }
@@ -7,7 +7,10 @@ package util
  */
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
+	"sort"
 	"strconv"
 
 	"github.com/GoFurry/gofurry-game-collector/roof/env"
@@ -30,3 +33,19 @@ func Float642String(f64 float64) string { return fmt.Sprintf("%.0f", f64) }
 
 // int 转字符串
 func Int2String(i int) string { return fmt.Sprintf("%d", i) }
+
+// 获取 map 按升序排列的 key
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// 字符串的 md5 摘要
+func MD5(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
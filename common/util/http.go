@@ -199,6 +199,41 @@ func PostByHttpWithParams(apiUrl string, headers map[string]string, params map[s
 	return string(body), nil
 }
 
+// DownloadByHttp 下载文件, 返回内容和 Content-Type, 超过 maxSize 字节或非 200 状态码时返回错误
+func DownloadByHttp(fileUrl string, headers map[string]string, maxSize int64, timeout time.Duration, proxy *string) ([]byte, string, error) {
+	// 创建请求
+	req, err := http.NewRequest("GET", fileUrl, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("创建请求失败: %w", err)
+	}
+
+	// 设置请求头
+	for k, v := range headers {
+		req.Header.Add(k, v)
+	}
+
+	// 发送请求
+	resp, err := getClientWithProxy(proxy, timeout).Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("发送GET请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("响应状态码错误: %d", resp.StatusCode)
+	}
+
+	// 读取响应, 多读一个字节判断是否超过大小限制
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("读取响应体失败: %w", err)
+	}
+	if int64(len(body)) > maxSize {
+		return nil, "", fmt.Errorf("文件超过大小限制: %d", maxSize)
+	}
+
+	return body, resp.Header.Get("Content-Type"), nil
+}
+
 // 工具函数：根据代理和超时时间获取客户端
 func getClientWithProxy(proxy *string, timeout time.Duration) *http.Client {
 	// 无需代理使用默认客户端
@@ -5,6 +5,7 @@ import (
 	"os"
 	"runtime/debug"
 
+	game "github.com/GoFurry/gofurry-game-collector/collector/game/controller"
 	"github.com/GoFurry/gofurry-game-collector/common"
 	"github.com/GoFurry/gofurry-game-collector/common/log"
 	cs "github.com/GoFurry/gofurry-game-collector/common/service"
@@ -54,6 +55,26 @@ func main() {
 			return
 		}
 
+		if os.Args[1] == "backfill" {
+			if len(os.Args) < 3 {
+				log.Error("用法: backfill <gameId>")
+				return
+			}
+			InitOnStart()
+			game.GameApi.BackfillGameNews(os.Args[2])
+			return
+		}
+
+		if os.Args[1] == "approve" || os.Args[1] == "reject" {
+			if len(os.Args) < 3 {
+				log.Error("用法: " + os.Args[1] + " <appid>")
+				return
+			}
+			InitOnStart()
+			game.GameApi.ReviewCandidate(os.Args[2], os.Args[1] == "approve")
+			return
+		}
+
 		if os.Args[1] == "version" {
 			log.Info("gf-game-collector V1.0.0")
 			return
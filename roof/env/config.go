@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/GoFurry/gofurry-game-collector/common"
 	"gopkg.in/yaml.v2"
@@ -22,6 +23,30 @@ type serverConfig struct {
 	Redis     RedisConfig     `yaml:"redis"`
 	Mongodb   MongodbConfig   `yaml:"mongodb"`
 	Collector CollectorConfig `yaml:"collector"`
+	Notifier  NotifierConfig  `yaml:"notifier"`
+	Admin     AdminConfig     `yaml:"admin"`
+}
+
+type AdminConfig struct {
+	Enable bool   `yaml:"enable"`
+	Addr   string `yaml:"addr"`
+	Token  string `yaml:"token"`
+}
+
+type NotifierConfig struct {
+	QueueSize int          `yaml:"queue_size"`
+	Retry     int          `yaml:"retry"`
+	Backoff   int          `yaml:"backoff"`
+	Sinks     []SinkConfig `yaml:"sinks"`
+}
+
+type SinkConfig struct {
+	Type     string   `yaml:"type"`
+	URL      string   `yaml:"url"`
+	Secret   string   `yaml:"secret"`
+	BotToken string   `yaml:"bot_token"`
+	ChatID   string   `yaml:"chat_id"`
+	Events   []string `yaml:"events"`
 }
 
 type MongodbConfig struct {
@@ -34,20 +59,78 @@ type MongodbConfig struct {
 }
 
 type CollectorConfig struct {
-	Proxy   string        `yaml:"proxy"`
-	Limiter LimiterConfig `yaml:"limiter"`
-	Game    GameConfig    `yaml:"game"`
+	Proxy    string         `yaml:"proxy"`
+	Limiter  LimiterConfig  `yaml:"limiter"`
+	Game     GameConfig     `yaml:"game"`
+	Sanitize SanitizeConfig `yaml:"sanitize"`
+	Mirror   MirrorConfig   `yaml:"mirror"`
+}
+
+type MirrorConfig struct {
+	Enable    bool     `yaml:"enable"`
+	Storage   string   `yaml:"storage"`
+	BaseURL   string   `yaml:"base_url"`
+	LocalPath string   `yaml:"local_path"`
+	MaxSize   int64    `yaml:"max_size"`
+	Video     bool     `yaml:"video"`
+	Widths    []int    `yaml:"widths"`
+	S3        S3Config `yaml:"s3"`
+}
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	PathStyle bool   `yaml:"path_style"`
+}
+
+type SanitizeConfig struct {
+	Tags        map[string][]string `yaml:"tags"`
+	Schemes     []string            `yaml:"schemes"`
+	IframeHosts []string            `yaml:"iframe_hosts"`
 }
 
 type LimiterConfig struct {
 	SteamApi   int `yaml:"steam_api"`
 	SteamStore int `yaml:"steam_store"`
+	Itch       int `yaml:"itch"`
+	Gog        int `yaml:"gog"`
 }
 
 type GameConfig struct {
-	GameThread         int `yaml:"game_thread"`
-	GameInterval       int `yaml:"game_interval"`
-	GamePlayerInterval int `yaml:"game_player_interval"`
+	GameThread         int              `yaml:"game_thread"`
+	GameInterval       int              `yaml:"game_interval"`
+	GamePlayerInterval int              `yaml:"game_player_interval"`
+	PlayerRawRetention int              `yaml:"player_raw_retention"`
+	PriceInterval      int              `yaml:"price_interval"`
+	PriceBatch         int              `yaml:"price_batch"`
+	Discovery          DiscoveryConfig  `yaml:"discovery"`
+	Regions            []RegionConfig   `yaml:"regions"`
+	Languages          []LanguageConfig `yaml:"languages"`
+}
+
+type DiscoveryConfig struct {
+	Interval int      `yaml:"interval"`
+	Tags     []int64  `yaml:"tags"`
+	Keywords []string `yaml:"keywords"`
+	MaxPages int      `yaml:"max_pages"`
+	CC       string   `yaml:"cc"`
+}
+
+type LanguageConfig struct {
+	Code           string `yaml:"code"`
+	Lang           string `yaml:"lang"`
+	CC             string `yaml:"cc"`
+	AcceptLanguage string `yaml:"accept_language"`
+}
+
+type RegionConfig struct {
+	CC             string `yaml:"cc"`
+	AcceptLanguage string `yaml:"accept_language"`
+	Lang           string `yaml:"lang"`
+	Currency       string `yaml:"currency"`
 }
 
 type ServerConfig struct {
@@ -87,21 +170,24 @@ func InitConfig(projectName string, fileName string, conf interface{}) {
 		}
 	}
 
-	//默认启动本地路径下conf.env
+	//默认启动本地路径下conf.env, 找不到时向上级目录查找, 便于在子目录执行 go test
 	if !hit {
 		pwd, err := os.Getwd()
 		if err != nil {
 			fmt.Println("Error loading pwd dir:", err.Error())
-		} else {
-			filePath := pwd + "/conf/" + fileName
+		}
+		for dir := pwd; err == nil && !hit; dir = filepath.Dir(dir) {
+			filePath := dir + "/conf/" + fileName
 			if FileExists(filePath) {
-				err = loadYaml(filePath, conf)
-				if err != nil {
+				if err = loadYaml(filePath, conf); err != nil {
 					fmt.Println("Error loading "+fileName+" file:", err.Error())
 				} else {
 					hit = true
 				}
 			}
+			if dir == filepath.Dir(dir) {
+				break
+			}
 		}
 	}
 